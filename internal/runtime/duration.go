@@ -0,0 +1,112 @@
+package runtime
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatDuration formats d as an ISO 8601 duration, e.g. PT5H or P1DT2H30M.
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	b := &strings.Builder{}
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteByte('P')
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 {
+		return b.String()
+	}
+	b.WriteByte('T')
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		b.WriteByte('S')
+	}
+	return b.String()
+}
+
+// ParseDuration parses an ISO 8601 duration such as PT5H or P1DT2H30M.
+// Years and months are rejected as their length is ambiguous.
+func ParseDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+	}
+	s = s[1:]
+	var d time.Duration
+	inTime := false
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+		i := strings.IndexAny(s, "WDHMS")
+		if i < 1 {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q", orig)
+		}
+		v, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %v", orig, err)
+		}
+		var unit time.Duration
+		switch {
+		case s[i] == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case s[i] == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case s[i] == 'H' && inTime:
+			unit = time.Hour
+		case s[i] == 'M' && inTime:
+			unit = time.Minute
+		case s[i] == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported ISO 8601 duration %q", orig)
+		}
+		d += time.Duration(v * float64(unit))
+		s = s[i+1:]
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
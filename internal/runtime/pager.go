@@ -0,0 +1,155 @@
+package runtime
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// Preparer prepares the request for the first page of a list operation.
+type Preparer func(ctx context.Context) (pipeline.Request, error)
+
+// Pager provides iteration over the pages of a list operation, following the nextLink of each page.
+// Each page is converted to a T by the Responder provided when the Pager was created.
+type Pager[T pipeline.Response] struct {
+	p         pipeline.Pipeline
+	prepare   Preparer
+	r         Responder
+	nextLink  func(T) *string
+	userAgent string
+	current   T
+	next      string
+	started   bool
+	err       error
+}
+
+// NewPager creates a Pager. The first page is requested with the request returned by prepare,
+// subsequent pages are requested from the URL returned by nextLink for the previous page.
+// No requests are sent until NextPage is called.
+func NewPager[T pipeline.Response](p pipeline.Pipeline, prepare Preparer, r Responder, nextLink func(T) *string) *Pager[T] {
+	return &Pager[T]{
+		p:        p,
+		prepare:  prepare,
+		r:        r,
+		nextLink: nextLink,
+	}
+}
+
+// NextPage fetches the next page of results. It returns false when there are no more
+// pages or an error occurred; call Err to distinguish between the two.
+func (p *Pager[T]) NextPage(ctx context.Context) bool {
+	if p.err != nil || (p.started && p.next == "") {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		p.err = err
+		return false
+	}
+	var req pipeline.Request
+	var err error
+	if !p.started {
+		req, err = p.prepare(ctx)
+		if err == nil {
+			p.userAgent = req.Header.Get(headerUserAgent)
+		}
+	} else {
+		req, err = p.nextPreparer()
+	}
+	if err != nil {
+		p.err = err
+		return false
+	}
+	resp, err := p.p.Do(ctx, NewResponderPolicyFactory(p.r), req)
+	if err != nil {
+		p.err = err
+		return false
+	}
+	p.started = true
+	p.current = resp.(T)
+	p.next = ""
+	if nl := p.nextLink(p.current); nl != nil {
+		p.next = *nl
+	}
+	return true
+}
+
+// PageResponse returns the current page. Its Response method returns the raw HTTP response for the page.
+func (p *Pager[T]) PageResponse() T {
+	return p.current
+}
+
+// Err returns the error, if any, that caused NextPage to return false.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Pages returns an iterator over the remaining pages. If an error occurs
+// it is yielded with a zero page and the iteration stops.
+func (p *Pager[T]) Pages(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.NextPage(ctx) {
+			if !yield(p.current, nil) {
+				return
+			}
+		}
+		if p.err != nil {
+			var zero T
+			yield(zero, p.err)
+		}
+	}
+}
+
+// All returns an iterator over the values in the remaining pages of p, as returned by values
+// for each page. If an error occurs it is yielded with a zero value and the iteration stops.
+func All[T pipeline.Response, V any](ctx context.Context, p *Pager[T], values func(T) []V) iter.Seq2[V, error] {
+	return func(yield func(V, error) bool) {
+		for page, err := range p.Pages(ctx) {
+			if err != nil {
+				var zero V
+				yield(zero, err)
+				return
+			}
+			for _, v := range values(page) {
+				if !yield(v, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// nextPreparer prepares the request for the page at the current nextLink.
+func (p *Pager[T]) nextPreparer() (pipeline.Request, error) {
+	u, err := url.Parse(p.next)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to parse next link")
+	}
+	req, err := pipeline.NewRequest(http.MethodGet, *u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	if p.userAgent != "" {
+		req.Header.Set(headerUserAgent, p.userAgent)
+	}
+	return req, nil
+}
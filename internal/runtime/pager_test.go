@@ -0,0 +1,233 @@
+package runtime
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const testUserAgent = "test-agent"
+
+// testPage is a page of the test list operation.
+type testPage struct {
+	rawResponse *http.Response
+	Value       []string `json:"value"`
+	NextLink    *string  `json:"nextLink"`
+}
+
+// Response returns the raw HTTP response object.
+func (p *testPage) Response() *http.Response {
+	return p.rawResponse
+}
+
+func testPageResponder(resp pipeline.Response) (pipeline.Response, error) {
+	if err := ValidateResponse(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	page := &testPage{rawResponse: resp.Response()}
+	if err := FromJSON(resp, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// pager creates a pager for the list operation starting at path.
+func (s *testServer) pager(path string) *Pager[*testPage] {
+	prepare := func(ctx context.Context) (pipeline.Request, error) {
+		u, err := url.Parse(s.URL + path)
+		if err != nil {
+			return pipeline.Request{}, err
+		}
+		req, err := pipeline.NewRequest(http.MethodGet, *u, nil)
+		if err != nil {
+			return req, err
+		}
+		req.Header.Set(headerUserAgent, testUserAgent)
+		return req, nil
+	}
+	return NewPager[*testPage](testPipeline(), prepare, testPageResponder, func(p *testPage) *string {
+		return p.NextLink
+	})
+}
+
+// onPages serves three pages of two values each at /items, /items/2 and /items/3.
+func (s *testServer) onPages() {
+	s.on(http.MethodGet, "/items", testResponse{status: http.StatusOK, body: `{"value":["a","b"],"nextLink":"{url}/items/2"}`})
+	s.on(http.MethodGet, "/items/2", testResponse{status: http.StatusOK, body: `{"value":["c","d"],"nextLink":"{url}/items/3"}`})
+	s.on(http.MethodGet, "/items/3", testResponse{status: http.StatusOK, body: `{"value":["e","f"]}`})
+}
+
+func TestPagerNextLink(t *testing.T) {
+	s := newTestServer(t)
+	s.onPages()
+	pager := s.pager("/items")
+	var values []string
+	for pager.NextPage(context.Background()) {
+		page := pager.PageResponse()
+		if page.Response().StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d", page.Response().StatusCode)
+		}
+		values = append(values, page.Value...)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a", "b", "c", "d", "e", "f"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+	// the last page has no nextLink
+	if pager.NextPage(context.Background()) {
+		t.Fatal("unexpected page after the last one")
+	}
+	for _, path := range []string{"/items", "/items/2", "/items/3"} {
+		if n := s.count(http.MethodGet, path); n != 1 {
+			t.Fatalf("expected one request to %s, got %d", path, n)
+		}
+		if ua := s.header(http.MethodGet, path).Get(headerUserAgent); ua != testUserAgent {
+			t.Fatalf("unexpected User-Agent %q for %s", ua, path)
+		}
+	}
+}
+
+func TestPagerCancelled(t *testing.T) {
+	s := newTestServer(t)
+	s.onPages()
+	pager := s.pager("/items")
+	ctx, cancel := context.WithCancel(context.Background())
+	if !pager.NextPage(ctx) {
+		t.Fatal(pager.Err())
+	}
+	cancel()
+	if pager.NextPage(ctx) {
+		t.Fatal("unexpected page after cancellation")
+	}
+	if err := pager.Err(); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := s.count(http.MethodGet, "/items/2"); n != 0 {
+		t.Fatalf("expected no request for the second page, got %d", n)
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodGet, "/items", testResponse{status: http.StatusOK, body: `{"value":["a","b"],"nextLink":"{url}/items/2"}`})
+	s.on(http.MethodGet, "/items/2", testResponse{status: http.StatusInternalServerError, body: `{"code":"InternalError"}`})
+	pager := s.pager("/items")
+	if !pager.NextPage(context.Background()) {
+		t.Fatal(pager.Err())
+	}
+	if pager.NextPage(context.Background()) {
+		t.Fatal("unexpected page for a failed request")
+	}
+	re, ok := pager.Err().(ResponseError)
+	if !ok || re.Response().StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected a ResponseError, got %v", pager.Err())
+	}
+	// the pager doesn't continue after an error
+	if pager.NextPage(context.Background()) {
+		t.Fatal("unexpected page after an error")
+	}
+	if n := s.count(http.MethodGet, "/items/2"); n != 1 {
+		t.Fatalf("expected one request for the second page, got %d", n)
+	}
+}
+
+func testPageValues(p *testPage) []string {
+	return p.Value
+}
+
+func TestPagerAll(t *testing.T) {
+	s := newTestServer(t)
+	s.onPages()
+	var values []string
+	for v, err := range All(context.Background(), s.pager("/items"), testPageValues) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	if expected := []string{"a", "b", "c", "d", "e", "f"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestPagerAllError(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodGet, "/items", testResponse{status: http.StatusOK, body: `{"value":["a","b"],"nextLink":"{url}/items/2"}`})
+	s.on(http.MethodGet, "/items/2", testResponse{status: http.StatusInternalServerError, body: `{"code":"InternalError"}`})
+	pager := s.pager("/items")
+	var values []string
+	var errs []error
+	for v, err := range All(context.Background(), pager, testPageValues) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(errs) > 0 {
+			t.Fatalf("unexpected value %q after an error", v)
+		}
+		values = append(values, v)
+	}
+	// the first page's values are yielded before the second page's error
+	if expected := []string{"a", "b"}; !reflect.DeepEqual(values, expected) {
+		t.Fatalf("expected %v, got %v", expected, values)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+	if errs[0] != pager.Err() {
+		t.Fatalf("expected Err to return %v, got %v", errs[0], pager.Err())
+	}
+}
+
+func TestPagerAllBreak(t *testing.T) {
+	s := newTestServer(t)
+	s.onPages()
+	for v, err := range All(context.Background(), s.pager("/items"), testPageValues) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v == "a" {
+			break
+		}
+	}
+	if n := s.count(http.MethodGet, "/items/2"); n != 0 {
+		t.Fatalf("expected no request for the second page, got %d", n)
+	}
+}
+
+func TestPagerPagesBreak(t *testing.T) {
+	s := newTestServer(t)
+	s.onPages()
+	pages := 0
+	for _, err := range s.pager("/items").Pages(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pages++; pages == 2 {
+			break
+		}
+	}
+	if n := s.count(http.MethodGet, "/items/3"); n != 0 {
+		t.Fatalf("expected no request for the third page, got %d", n)
+	}
+}
@@ -0,0 +1,397 @@
+package runtime
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const (
+	headerAsyncOperation = "Azure-AsyncOperation"
+	headerLocation       = "Location"
+	headerRetryAfter     = "Retry-After"
+	headerUserAgent      = "User-Agent"
+)
+
+const (
+	statusInProgress = "InProgress"
+	statusSucceeded  = "Succeeded"
+	statusFailed     = "Failed"
+	statusCanceled   = "Canceled"
+)
+
+// pollingMethod describes how the status of a long-running operation is obtained.
+type pollingMethod string
+
+const (
+	// pollAsyncOperation polls the URL in the Azure-AsyncOperation header for a status object.
+	pollAsyncOperation pollingMethod = "AzureAsyncOperation"
+	// pollLocation polls the URL in the Location header until it no longer returns 202.
+	pollLocation pollingMethod = "Location"
+	// pollBody polls the resource URL for its provisioningState.
+	pollBody pollingMethod = "Body"
+	// pollNone is used when the initial response is terminal.
+	pollNone pollingMethod = "None"
+)
+
+// pollerState contains the state required to poll a long-running operation.
+// It is serialized to create resume tokens.
+type pollerState struct {
+	Operation string        `json:"operation"`
+	Method    string        `json:"method"`
+	URL       string        `json:"url"`
+	FinalURL  string        `json:"finalURL,omitempty"`
+	PollURL   string        `json:"pollURL"`
+	Polling   pollingMethod `json:"polling"`
+	Status    string        `json:"status"`
+	UserAgent string        `json:"userAgent,omitempty"`
+}
+
+// Poller tracks the state of a long-running operation and polls it until it reaches a terminal state.
+// The final result of the operation is converted to a T by the Responder provided when the Poller was created.
+type Poller[T pipeline.Response] struct {
+	p     pipeline.Pipeline
+	r     Responder
+	state pollerState
+	// resp is the most recent response; it contains the result when
+	// the operation's final state is carried in a polling response.
+	resp pipeline.Response
+}
+
+// NewPoller creates a Poller from the successful initial response of a long-running operation.
+// The polling method is selected from the Azure-AsyncOperation and Location response headers,
+// falling back to polling the resource's provisioningState for PUT and PATCH requests.
+// The operation identifies the long-running operation in resume tokens (e.g. "redis.Create").
+func NewPoller[T pipeline.Response](operation string, p pipeline.Pipeline, resp pipeline.Response, r Responder) (*Poller[T], error) {
+	if resp == nil || resp.Response() == nil || resp.Response().Request == nil {
+		return nil, pipeline.NewError(nil, "the initial response does not contain the originating request")
+	}
+	req := resp.Response().Request
+	poller := &Poller[T]{
+		p: p,
+		r: r,
+		state: pollerState{
+			Operation: operation,
+			Method:    req.Method,
+			URL:       req.URL.String(),
+			Status:    statusInProgress,
+			UserAgent: req.Header.Get(headerUserAgent),
+		},
+	}
+	if req.Method == http.MethodPut || req.Method == http.MethodPatch {
+		poller.state.FinalURL = poller.state.URL
+	}
+	if err := poller.update(resp); err != nil {
+		return nil, err
+	}
+	return poller, nil
+}
+
+// NewPollerFromResumeToken creates a Poller from a token returned by ResumeToken.
+// The operation must match the one the token was created for.
+func NewPollerFromResumeToken[T pipeline.Response](operation string, p pipeline.Pipeline, token string, r Responder) (*Poller[T], error) {
+	var state pollerState
+	if err := json.Unmarshal([]byte(token), &state); err != nil {
+		return nil, pipeline.NewError(err, "failed to unmarshal resume token")
+	}
+	if state.Operation != operation {
+		return nil, pipeline.NewError(nil, fmt.Sprintf("resume token is for operation '%s', not '%s'", state.Operation, operation))
+	}
+	if state.PollURL == "" && state.Polling != pollNone {
+		return nil, pipeline.NewError(nil, "resume token is missing the polling URL")
+	}
+	return &Poller[T]{p: p, r: r, state: state}, nil
+}
+
+// ResumeToken returns a token that can be persisted and later passed to
+// NewPollerFromResumeToken to continue polling the operation.
+func (p *Poller[T]) ResumeToken() string {
+	b, err := json.Marshal(p.state)
+	if err != nil {
+		// pollerState contains only strings
+		panic(err)
+	}
+	return string(b)
+}
+
+// update advances the poller's state based on the specified response.
+func (p *Poller[T]) update(resp pipeline.Response) error {
+	resp, err := bufferBody(resp)
+	if err != nil {
+		return err
+	}
+	p.resp = resp
+	h := resp.Response().Header
+	if u := h.Get(headerAsyncOperation); u != "" {
+		p.state.Polling = pollAsyncOperation
+		p.state.PollURL = u
+		if l := h.Get(headerLocation); l != "" && p.state.Method == http.MethodPost {
+			// the result of a POST operation can be retrieved from the Location URL
+			p.state.FinalURL = l
+		}
+		return nil
+	}
+	if u := h.Get(headerLocation); u != "" {
+		p.state.Polling = pollLocation
+		p.state.PollURL = u
+		return nil
+	}
+	if p.state.Method == http.MethodPut || p.state.Method == http.MethodPatch {
+		p.state.Polling = pollBody
+		p.state.PollURL = p.state.URL
+		return p.updateFromProvisioningState(resp)
+	}
+	// no polling headers; the initial response is the final response
+	p.state.Polling = pollNone
+	p.state.Status = statusSucceeded
+	return nil
+}
+
+// Done returns true if the long-running operation has reached a terminal state.
+func (p *Poller[T]) Done() bool {
+	return p.state.Status == statusSucceeded || p.state.Status == statusFailed || p.state.Status == statusCanceled
+}
+
+// Poll sends a single polling request and updates the poller's state.
+// Calling Poll once the operation has reached a terminal state returns the last response.
+func (p *Poller[T]) Poll(ctx context.Context) (*http.Response, error) {
+	if p.Done() {
+		if p.resp == nil {
+			// resumed from the token of a completed operation
+			return nil, p.failed()
+		}
+		return p.resp.Response(), p.failed()
+	}
+	resp, err := p.getURL(ctx, p.state.PollURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp, err = bufferBody(resp); err != nil {
+		return nil, err
+	}
+	p.resp = resp
+	switch p.state.Polling {
+	case pollAsyncOperation:
+		if err = ValidateResponse(resp, http.StatusOK, http.StatusAccepted); err != nil {
+			return nil, err
+		}
+		if u := resp.Response().Header.Get(headerAsyncOperation); u != "" {
+			p.state.PollURL = u
+		}
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err = FromJSON(resp, &status); err != nil {
+			return nil, err
+		}
+		if status.Status == "" {
+			return nil, NewResponseError(nil, resp.Response(), "the status of the long-running operation is missing")
+		}
+		p.state.Status = normalizeStatus(status.Status)
+	case pollLocation:
+		if err = ValidateResponse(resp, http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent); err != nil {
+			return nil, err
+		}
+		if resp.Response().StatusCode != http.StatusAccepted {
+			p.state.Status = statusSucceeded
+		} else if u := resp.Response().Header.Get(headerLocation); u != "" {
+			p.state.PollURL = u
+		}
+	case pollBody:
+		if err = ValidateResponse(resp, http.StatusOK); err != nil {
+			return nil, err
+		}
+		if err = p.updateFromProvisioningState(resp); err != nil {
+			return nil, err
+		}
+	}
+	// the body was buffered, rewind it for the caller
+	if resp, err = bufferBody(p.resp); err != nil {
+		return nil, err
+	}
+	p.resp = resp
+	return resp.Response(), p.failed()
+}
+
+// Result returns the final result of the long-running operation.
+// It returns an error if the operation hasn't reached a terminal state or did not succeed.
+func (p *Poller[T]) Result(ctx context.Context) (T, error) {
+	var zero T
+	if !p.Done() {
+		return zero, pipeline.NewError(nil, "the long-running operation has not completed")
+	}
+	if err := p.failed(); err != nil {
+		return zero, err
+	}
+	var resp pipeline.Response
+	var err error
+	switch {
+	case p.state.FinalURL != "" && (p.state.Polling == pollAsyncOperation || p.state.Polling == pollLocation):
+		resp, err = p.getURL(ctx, p.state.FinalURL, p.r)
+	case p.resp != nil:
+		// the last response contains the final state of the operation
+		if resp, err = bufferBody(p.resp); err == nil {
+			resp, err = p.r(resp)
+		}
+	case p.state.Polling == pollBody || p.state.Polling == pollLocation:
+		// the poller was resumed after the operation completed, fetch the final state again
+		resp, err = p.getURL(ctx, p.state.PollURL, p.r)
+	default:
+		return zero, pipeline.NewError(nil, "the result of the long-running operation is not available")
+	}
+	if err != nil {
+		return zero, err
+	}
+	return resp.(T), nil
+}
+
+// PollUntilDone polls the long-running operation until it reaches a terminal state, then
+// returns its final result. The frequency is used as the delay between polls unless the
+// service specifies one with the Retry-After header.
+func (p *Poller[T]) PollUntilDone(ctx context.Context, frequency time.Duration) (T, error) {
+	for !p.Done() {
+		if _, err := p.Poll(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		if p.Done() {
+			break
+		}
+		d := frequency
+		if ra, err := strconv.Atoi(p.resp.Response().Header.Get(headerRetryAfter)); err == nil && ra > 0 {
+			d = time.Duration(ra) * time.Second
+		}
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+	return p.Result(ctx)
+}
+
+// getURL sends a GET request for the specified URL string through the pipeline.
+func (p *Poller[T]) getURL(ctx context.Context, s string, r Responder) (pipeline.Response, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, pipeline.NewError(err, "failed to parse URL")
+	}
+	return p.get(ctx, *u, r)
+}
+
+// get sends a GET request for the specified URL through the pipeline.
+func (p *Poller[T]) get(ctx context.Context, u url.URL, r Responder) (pipeline.Response, error) {
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, pipeline.NewError(err, "failed to create request")
+	}
+	if p.state.UserAgent != "" {
+		req.Header.Set(headerUserAgent, p.state.UserAgent)
+	}
+	var f pipeline.Factory
+	if r != nil {
+		f = NewResponderPolicyFactory(r)
+	}
+	return p.p.Do(ctx, f, req)
+}
+
+// updateFromProvisioningState sets the status from the properties.provisioningState field of
+// the response body. A missing provisioningState indicates the operation has succeeded.
+func (p *Poller[T]) updateFromProvisioningState(resp pipeline.Response) error {
+	var body struct {
+		Properties struct {
+			ProvisioningState string `json:"provisioningState"`
+		} `json:"properties"`
+	}
+	if err := FromJSON(resp, &body); err != nil {
+		return err
+	}
+	if state := body.Properties.ProvisioningState; state == "" {
+		p.state.Status = statusSucceeded
+	} else {
+		p.state.Status = normalizeStatus(state)
+	}
+	_, err := bufferBody(resp)
+	return err
+}
+
+// normalizeStatus returns the terminal status matching status regardless of case,
+// or status unchanged if it isn't a terminal status.
+func normalizeStatus(status string) string {
+	for _, terminal := range []string{statusSucceeded, statusFailed, statusCanceled} {
+		if strings.EqualFold(status, terminal) {
+			return terminal
+		}
+	}
+	return status
+}
+
+// failed returns an error if the operation reached an unsuccessful terminal state.
+func (p *Poller[T]) failed() error {
+	if p.state.Status != statusFailed && p.state.Status != statusCanceled {
+		return nil
+	}
+	if p.resp == nil {
+		return pipeline.NewError(nil, fmt.Sprintf("the long-running operation terminated with status '%s'", p.state.Status))
+	}
+	return NewResponseError(nil, p.resp.Response(), fmt.Sprintf("the long-running operation terminated with status '%s'", p.state.Status))
+}
+
+// bufferBody reads the response body into memory so it can be read multiple times.
+func bufferBody(resp pipeline.Response) (pipeline.Response, error) {
+	r := resp.Response()
+	if b, ok := r.Body.(*bufferedBody); ok {
+		b.Reset()
+		return resp, nil
+	}
+	defer r.Body.Close()
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, NewResponseError(err, r, "failed to read response body")
+	}
+	r.Body = &bufferedBody{b: b, Reader: bytes.NewReader(b)}
+	return resp, nil
+}
+
+// bufferedBody is a response body held in memory.
+type bufferedBody struct {
+	*bytes.Reader
+	b []byte
+}
+
+// Reset rewinds the body to its beginning.
+func (b *bufferedBody) Reset() {
+	b.Reader.Reset(b.b)
+}
+
+// Close implements io.Closer; closing a buffered body is a no-op.
+func (*bufferedBody) Close() error {
+	return nil
+}
@@ -0,0 +1,322 @@
+package runtime
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const testOperation = "test.Operation"
+
+// testResult is the final result of the test operations.
+type testResult struct {
+	rawResponse *http.Response
+	Name        string `json:"name"`
+}
+
+// Response returns the raw HTTP response object.
+func (r *testResult) Response() *http.Response {
+	return r.rawResponse
+}
+
+func testResponder(resp pipeline.Response) (pipeline.Response, error) {
+	if err := ValidateResponse(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	result := &testResult{rawResponse: resp.Response()}
+	if err := FromJSON(resp, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// testServer serves the operation under test; each URL returns its
+// responses in order, repeating the last one. "{url}" in a response's
+// headers or body is replaced with the server's URL.
+type testServer struct {
+	*httptest.Server
+	lock      sync.Mutex
+	responses map[string][]testResponse
+	requests  map[string]int
+	headers   map[string]http.Header
+}
+
+type testResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := &testServer{responses: map[string][]testResponse{}, requests: map[string]int{}, headers: map[string]http.Header{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		key := r.Method + " " + r.URL.Path
+		responses, ok := s.responses[key]
+		if !ok {
+			t.Errorf("unexpected request %s", key)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		n := s.requests[key]
+		s.requests[key]++
+		s.headers[key] = r.Header
+		if n >= len(responses) {
+			n = len(responses) - 1
+		}
+		for k, v := range responses[n].headers {
+			w.Header().Set(k, strings.Replace(v, "{url}", s.URL, 1))
+		}
+		w.WriteHeader(responses[n].status)
+		fmt.Fprint(w, strings.Replace(responses[n].body, "{url}", s.URL, -1))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *testServer) on(method string, path string, responses ...testResponse) {
+	s.responses[method+" "+path] = responses
+}
+
+func (s *testServer) count(method string, path string) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.requests[method+" "+path]
+}
+
+// header returns the headers of the last request to path.
+func (s *testServer) header(method string, path string) http.Header {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.headers[method+" "+path]
+}
+
+func testPipeline() pipeline.Pipeline {
+	return pipeline.NewPipeline([]pipeline.Factory{pipeline.MethodFactoryMarker()}, pipeline.Options{})
+}
+
+// start sends the initial request of the operation and creates a poller from its response.
+func (s *testServer) start(t *testing.T, method string, path string) *Poller[*testResult] {
+	u, _ := url.Parse(s.URL + path)
+	req, err := pipeline.NewRequest(method, *u, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := testPipeline().Do(context.Background(), nil, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	poller, err := NewPoller[*testResult](testOperation, testPipeline(), resp, testResponder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return poller
+}
+
+func TestPollerAsyncOperation(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodDelete, "/resource", testResponse{status: http.StatusAccepted, headers: map[string]string{"Azure-AsyncOperation": "{url}/status"}})
+	// the status's case differs from the constants
+	s.on(http.MethodGet, "/status",
+		testResponse{status: http.StatusOK, body: `{"status":"inProgress"}`},
+		testResponse{status: http.StatusOK, body: `{"status":"succeeded","name":"done"}`})
+	poller := s.start(t, http.MethodDelete, "/resource")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := poller.PollUntilDone(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "done" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if n := s.count(http.MethodGet, "/status"); n != 2 {
+		t.Fatalf("expected 2 polls, got %d", n)
+	}
+}
+
+func TestPollerAsyncOperationFailed(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPut, "/resource", testResponse{status: http.StatusCreated, headers: map[string]string{"Azure-AsyncOperation": "{url}/status"}})
+	s.on(http.MethodGet, "/status", testResponse{status: http.StatusOK, body: `{"status":"failed"}`})
+	poller := s.start(t, http.MethodPut, "/resource")
+	if _, err := poller.PollUntilDone(context.Background(), time.Millisecond); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !poller.Done() {
+		t.Fatal("the poller isn't done")
+	}
+	if _, err := poller.Result(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestPollerAsyncOperationFinalURL(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPost, "/resource/export", testResponse{status: http.StatusAccepted, headers: map[string]string{
+		"Azure-AsyncOperation": "{url}/status",
+		"Location":             "{url}/result",
+	}})
+	s.on(http.MethodGet, "/status", testResponse{status: http.StatusOK, body: `{"status":"Succeeded"}`})
+	s.on(http.MethodGet, "/result", testResponse{status: http.StatusOK, body: `{"name":"exported"}`})
+	poller := s.start(t, http.MethodPost, "/resource/export")
+	result, err := poller.PollUntilDone(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "exported" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPollerLocation(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPost, "/resource/import", testResponse{status: http.StatusAccepted, headers: map[string]string{"Location": "{url}/location"}})
+	s.on(http.MethodGet, "/location",
+		testResponse{status: http.StatusAccepted, headers: map[string]string{"Location": "{url}/location2"}})
+	s.on(http.MethodGet, "/location2",
+		testResponse{status: http.StatusAccepted},
+		testResponse{status: http.StatusOK, body: `{"name":"imported"}`})
+	poller := s.start(t, http.MethodPost, "/resource/import")
+	result, err := poller.PollUntilDone(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "imported" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if n := s.count(http.MethodGet, "/location2"); n != 2 {
+		t.Fatalf("expected 2 polls of the updated Location, got %d", n)
+	}
+}
+
+func TestPollerBody(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPut, "/resource", testResponse{status: http.StatusCreated, body: `{"name":"r","properties":{"provisioningState":"Creating"}}`})
+	s.on(http.MethodGet, "/resource",
+		testResponse{status: http.StatusOK, body: `{"name":"r","properties":{"provisioningState":"Creating"}}`},
+		testResponse{status: http.StatusOK, body: `{"name":"r","properties":{"provisioningState":"SUCCEEDED"}}`})
+	poller := s.start(t, http.MethodPut, "/resource")
+	if poller.Done() {
+		t.Fatal("the poller is done")
+	}
+	result, err := poller.PollUntilDone(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "r" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPollerNone(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPost, "/resource/action", testResponse{status: http.StatusOK, body: `{"name":"immediate"}`})
+	poller := s.start(t, http.MethodPost, "/resource/action")
+	if !poller.Done() {
+		t.Fatal("the poller isn't done")
+	}
+	result, err := poller.Result(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "immediate" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPollerResumeToken(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPut, "/resource", testResponse{status: http.StatusCreated, headers: map[string]string{"Azure-AsyncOperation": "{url}/status"}})
+	s.on(http.MethodGet, "/status",
+		testResponse{status: http.StatusOK, body: `{"status":"InProgress"}`},
+		testResponse{status: http.StatusOK, body: `{"status":"Succeeded"}`})
+	s.on(http.MethodGet, "/resource", testResponse{status: http.StatusOK, body: `{"name":"resumed"}`})
+	poller := s.start(t, http.MethodPut, "/resource")
+	if _, err := poller.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	token := poller.ResumeToken()
+	if _, err := NewPollerFromResumeToken[*testResult]("other.Operation", testPipeline(), token, testResponder); err == nil {
+		t.Fatal("expected an error for a token of another operation")
+	}
+	resumed, err := NewPollerFromResumeToken[*testResult](testOperation, testPipeline(), token, testResponder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.ResumeToken() != token {
+		t.Fatalf("the resumed poller's token %s differs from %s", resumed.ResumeToken(), token)
+	}
+	result, err := resumed.PollUntilDone(context.Background(), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the result of a PUT is fetched from the resource's URL
+	if result.Name != "resumed" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPollerResumeAfterCompletion(t *testing.T) {
+	s := newTestServer(t)
+	s.on(http.MethodPut, "/resource", testResponse{status: http.StatusOK, body: `{"name":"r","properties":{"provisioningState":"Succeeded"}}`})
+	s.on(http.MethodGet, "/resource", testResponse{status: http.StatusOK, body: `{"name":"refetched"}`})
+	poller := s.start(t, http.MethodPut, "/resource")
+	if !poller.Done() {
+		t.Fatal("the poller isn't done")
+	}
+	resumed, err := NewPollerFromResumeToken[*testResult](testOperation, testPipeline(), poller.ResumeToken(), testResponder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resumed.Done() {
+		t.Fatal("the resumed poller isn't done")
+	}
+	if _, err := resumed.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	result, err := resumed.Result(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Name != "refetched" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestNormalizeStatus(t *testing.T) {
+	for in, want := range map[string]string{
+		"succeeded":  statusSucceeded,
+		"FAILED":     statusFailed,
+		"canceled":   statusCanceled,
+		"Updating":   "Updating",
+		"InProgress": statusInProgress,
+	} {
+		if got := normalizeStatus(in); got != want {
+			t.Errorf("normalizeStatus(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
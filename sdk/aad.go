@@ -0,0 +1,161 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAuthorityHost is the authority host for the Azure public cloud.
+	DefaultAuthorityHost = "https://login.microsoftonline.com/"
+
+	// DefaultScope is the scope requested when a credential isn't configured with any scopes.
+	DefaultScope = "https://management.azure.com/.default"
+)
+
+// AuthenticationFailedError is returned when Azure Active Directory rejects a token request.
+type AuthenticationFailedError struct {
+	// StatusCode is the HTTP status code of the token response.
+	StatusCode int
+	// Code is the error code returned by the service, e.g. invalid_client.
+	Code string
+	// Description is the error description returned by the service.
+	Description string
+}
+
+// Error implements the error interface's Error method.
+func (e *AuthenticationFailedError) Error() string {
+	return fmt.Sprintf("authentication failed (Status=%d, Code=%s): %s", e.StatusCode, e.Code, e.Description)
+}
+
+// CredentialUnavailableError is returned when a credential can't be used in the current environment,
+// e.g. because it isn't configured or the identity endpoint it relies on isn't reachable.
+type CredentialUnavailableError struct {
+	// CredentialType is the name of the credential type, e.g. EnvironmentCredential.
+	CredentialType string
+	// Message describes why the credential is unavailable.
+	Message string
+}
+
+// Error implements the error interface's Error method.
+func (e *CredentialUnavailableError) Error() string {
+	return fmt.Sprintf("%s is unavailable: %s", e.CredentialType, e.Message)
+}
+
+// aadClient sends token requests to the v2.0 token endpoint of an Azure Active Directory tenant.
+type aadClient struct {
+	authorityHost string
+	tenantID      string
+	httpClient    *http.Client
+}
+
+// newAADClient creates an aadClient, applying defaults for an empty authorityHost and a nil httpClient.
+func newAADClient(tenantID string, authorityHost string, httpClient *http.Client) (*aadClient, error) {
+	if tenantID == "" {
+		return nil, fmt.Errorf("tenantID can't be empty")
+	}
+	if authorityHost == "" {
+		authorityHost = DefaultAuthorityHost
+	}
+	if _, err := url.Parse(authorityHost); err != nil {
+		return nil, fmt.Errorf("invalid authority host %q: %v", authorityHost, err)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &aadClient{
+		authorityHost: strings.TrimSuffix(authorityHost, "/"),
+		tenantID:      tenantID,
+		httpClient:    httpClient,
+	}, nil
+}
+
+// tokenEndpoint returns the URL of the token endpoint for the tenant requested in opts,
+// or the client's tenant if opts doesn't specify one.
+func (c *aadClient) tokenEndpoint(opts TokenRequestOptions) string {
+	tenantID := c.tenantID
+	if opts.TenantID != "" {
+		tenantID = opts.TenantID
+	}
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", c.authorityHost, url.PathEscape(tenantID))
+}
+
+// requestToken posts form, which must contain the grant and client parameters, to the token endpoint.
+func (c *aadClient) requestToken(ctx context.Context, opts TokenRequestOptions, form url.Values) (AccessToken, error) {
+	form.Set("scope", strings.Join(opts.Scopes, " "))
+	if opts.Claims != "" {
+		form.Set("claims", opts.Claims)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.tokenEndpoint(opts), strings.NewReader(form.Encode()))
+	if err != nil {
+		return AccessToken{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return parseTokenResponse(resp)
+}
+
+// parseTokenResponse decodes an OAuth2 token response. The method always closes the response body.
+func parseTokenResponse(resp *http.Response) (AccessToken, error) {
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		authErr := &AuthenticationFailedError{StatusCode: resp.StatusCode, Description: resp.Status}
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(b, &body) == nil && body.Error != "" {
+			authErr.Code = body.Error
+			authErr.Description = body.ErrorDescription
+		}
+		return AccessToken{}, authErr
+	}
+	var body struct {
+		AccessToken string `json:"access_token"`
+		// some endpoints return these as strings
+		ExpiresIn json.Number `json:"expires_in"`
+		ExpiresOn json.Number `json:"expires_on"`
+	}
+	if err = json.Unmarshal(b, &body); err != nil {
+		return AccessToken{}, fmt.Errorf("failed to unmarshal token response: %v", err)
+	}
+	if body.AccessToken == "" {
+		return AccessToken{}, fmt.Errorf("token response doesn't contain an access token")
+	}
+	if secs, err := body.ExpiresIn.Int64(); err == nil {
+		return AccessToken{Token: body.AccessToken, ExpiresOn: time.Now().Add(time.Duration(secs) * time.Second)}, nil
+	}
+	// managed identity endpoints may only return the expiry as seconds since the epoch
+	if secs, err := body.ExpiresOn.Int64(); err == nil {
+		return AccessToken{Token: body.AccessToken, ExpiresOn: time.Unix(secs, 0)}, nil
+	}
+	return AccessToken{}, fmt.Errorf("token response doesn't contain a valid expiry")
+}
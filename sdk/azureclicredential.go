@@ -0,0 +1,174 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const (
+	defaultCLIPath    = "az"
+	defaultCLITimeout = 10 * time.Second
+
+	// cliExpiresOnLayout is the layout of expiresOn in the CLI's output, it's in local time.
+	cliExpiresOnLayout = "2006-01-02 15:04:05.999999"
+)
+
+// AzureCLICredentialOptions contains optional parameters for NewAzureCLICredential.
+type AzureCLICredentialOptions struct {
+	// CLIPath is the path of the Azure CLI executable. Defaults to az, found using the PATH environment variable.
+	CLIPath string
+
+	// TenantID is the tenant to request tokens from. Defaults to the tenant of the CLI's current subscription.
+	TenantID string
+
+	// Timeout is how long the CLI may run before it's killed. Defaults to ten seconds.
+	Timeout time.Duration
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+}
+
+// AzureCLICredential authenticates as the user logged in to the Azure CLI by running
+// `az account get-access-token`. It's intended for development, not for production use.
+type AzureCLICredential struct {
+	cliPath  string
+	tenantID string
+	timeout  time.Duration
+	bearer   *bearerTokenCredential
+}
+
+// NewAzureCLICredential creates an AzureCLICredential. Pass nil for options to accept the default values.
+func NewAzureCLICredential(options *AzureCLICredentialOptions) (*AzureCLICredential, error) {
+	if options == nil {
+		options = &AzureCLICredentialOptions{}
+	}
+	c := &AzureCLICredential{
+		cliPath:  options.CLIPath,
+		tenantID: options.TenantID,
+		timeout:  options.Timeout,
+	}
+	if c.cliPath == "" {
+		c.cliPath = defaultCLIPath
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultCLITimeout
+	}
+	c.bearer = newBearerTokenCredential(c, options.Scopes)
+	return c, nil
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*AzureCLICredential) credentialMarker() {}
+
+// GetToken runs the Azure CLI to get a new token for the requested scope.
+// The Azure CLI only supports requesting a token for a single scope.
+func (c *AzureCLICredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	if len(opts.Scopes) != 1 {
+		return AccessToken{}, fmt.Errorf("the Azure CLI requires exactly one scope, got %d", len(opts.Scopes))
+	}
+	tenantID := c.tenantID
+	if opts.TenantID != "" {
+		tenantID = opts.TenantID
+	}
+	if tenantID != "" && !validTenantID(tenantID) {
+		return AccessToken{}, fmt.Errorf("invalid tenant %q", tenantID)
+	}
+	resource := strings.TrimSuffix(opts.Scopes[0], "/.default")
+	if strings.HasPrefix(resource, "-") {
+		return AccessToken{}, fmt.Errorf("invalid scope %q", opts.Scopes[0])
+	}
+	out, err := c.run(ctx, resource, tenantID)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return parseCLIToken(out)
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *AzureCLICredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
+
+// run invokes the CLI to get a token for resource, from tenantID if it isn't empty, and returns its output.
+func (c *AzureCLICredential) run(ctx context.Context, resource string, tenantID string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	args := []string{"account", "get-access-token", "--output", "json", "--resource", resource}
+	if tenantID != "" {
+		args = append(args, "--tenant", tenantID)
+	}
+	cmd := exec.CommandContext(ctx, c.cliPath, args...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err == nil {
+		return out, nil
+	}
+	if errors.Is(err, exec.ErrNotFound) || errors.Is(err, os.ErrNotExist) {
+		return nil, &CredentialUnavailableError{
+			CredentialType: "AzureCLICredential",
+			Message:        fmt.Sprintf("the Azure CLI wasn't found at %q", c.cliPath),
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("AzureCLICredential: the Azure CLI didn't respond within %v", c.timeout)
+	}
+	msg := strings.TrimSpace(stderr.String())
+	if strings.Contains(msg, "az login") || strings.Contains(msg, "az account set") {
+		return nil, &CredentialUnavailableError{
+			CredentialType: "AzureCLICredential",
+			Message:        "please run 'az login' to set up an account",
+		}
+	}
+	if msg == "" {
+		msg = err.Error()
+	}
+	return nil, fmt.Errorf("AzureCLICredential: %s", msg)
+}
+
+// parseCLIToken parses the output of `az account get-access-token`.
+func parseCLIToken(out []byte) (AccessToken, error) {
+	var body struct {
+		AccessToken string `json:"accessToken"`
+		ExpiresOn   string `json:"expiresOn"`
+		// recent versions of the CLI also include the expiry as seconds since the epoch
+		ExpiresOnUnix int64 `json:"expires_on"`
+	}
+	if err := json.Unmarshal(out, &body); err != nil {
+		return AccessToken{}, fmt.Errorf("failed to unmarshal Azure CLI output: %v", err)
+	}
+	if body.AccessToken == "" {
+		return AccessToken{}, fmt.Errorf("Azure CLI output doesn't contain an access token")
+	}
+	if body.ExpiresOnUnix > 0 {
+		return AccessToken{Token: body.AccessToken, ExpiresOn: time.Unix(body.ExpiresOnUnix, 0)}, nil
+	}
+	exp, err := time.ParseInLocation(cliExpiresOnLayout, body.ExpiresOn, time.Local)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("invalid expiresOn %q in Azure CLI output: %v", body.ExpiresOn, err)
+	}
+	return AccessToken{Token: body.AccessToken, ExpiresOn: exp}, nil
+}
@@ -0,0 +1,165 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFakeCLI writes a shell script standing in for the Azure CLI and returns its path.
+// The script records its arguments in the file returned second.
+func writeFakeCLI(t *testing.T, script string) (string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake Azure CLI is a shell script")
+	}
+	dir := t.TempDir()
+	path, args := filepath.Join(dir, "az"), filepath.Join(dir, "args")
+	script = "#!/bin/sh\necho \"$@\" > '" + args + "'\n" + script
+	if err := ioutil.WriteFile(path, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return path, args
+}
+
+func TestAzureCLICredential(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		output string
+		expiry time.Time
+	}{
+		{
+			name:   "expiresOn",
+			output: `{"accessToken":"token","expiresOn":"2030-01-02 03:04:05.123456","subscription":"sub","tenant":"tenant","tokenType":"Bearer"}`,
+			expiry: time.Date(2030, 1, 2, 3, 4, 5, 123456000, time.Local),
+		},
+		{
+			name:   "expires_on",
+			output: `{"accessToken":"token","expiresOn":"2030-01-02 03:04:05.123456","expires_on":1893553445,"tokenType":"Bearer"}`,
+			expiry: time.Unix(1893553445, 0),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path, args := writeFakeCLI(t, "echo '"+tc.output+"'\n")
+			cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: path, TenantID: "tenant"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tk, err := cred.GetToken(context.Background(), testTokenOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tk.Token != "token" || !tk.ExpiresOn.Equal(tc.expiry) {
+				t.Fatalf("unexpected token %q expiring %v", tk.Token, tk.ExpiresOn)
+			}
+			b, err := ioutil.ReadFile(args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := "account get-access-token --output json --resource https://management.azure.com --tenant tenant"
+			if got := strings.TrimSpace(string(b)); got != expected {
+				t.Fatalf("expected arguments %q, got %q", expected, got)
+			}
+		})
+	}
+}
+
+func TestAzureCLICredentialChallengeTenant(t *testing.T) {
+	path, args := writeFakeCLI(t, `echo '{"accessToken":"token","expires_on":1893553445}'`+"\n")
+	cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cred.GetToken(context.Background(), TokenRequestOptions{Scopes: []string{"https://vault.azure.net/.default"}, TenantID: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(b)); !strings.HasSuffix(got, "--resource https://vault.azure.net --tenant other") {
+		t.Fatalf("unexpected arguments %q", got)
+	}
+	// invalid values are rejected rather than passed to the CLI
+	for _, opts := range []TokenRequestOptions{
+		{Scopes: []string{DefaultScope}, TenantID: "--debug"},
+		{Scopes: []string{"--debug"}},
+	} {
+		if _, err = cred.GetToken(context.Background(), opts); err == nil {
+			t.Fatalf("expected an error for %+v", opts)
+		}
+	}
+}
+
+func TestAzureCLICredentialNotLoggedIn(t *testing.T) {
+	path, _ := writeFakeCLI(t, "echo \"ERROR: Please run 'az login' to setup account.\" >&2\nexit 1\n")
+	cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), testTokenOptions)
+	var unavailable *CredentialUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected a CredentialUnavailableError, got %v", err)
+	}
+}
+
+func TestAzureCLICredentialFailure(t *testing.T) {
+	path, _ := writeFakeCLI(t, "echo 'ERROR: something went wrong' >&2\nexit 1\n")
+	cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), testTokenOptions)
+	var unavailable *CredentialUnavailableError
+	if err == nil || errors.As(err, &unavailable) || !strings.Contains(err.Error(), "something went wrong") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestAzureCLICredentialNotInstalled(t *testing.T) {
+	cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: filepath.Join(t.TempDir(), "az")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), testTokenOptions)
+	var unavailable *CredentialUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected a CredentialUnavailableError, got %v", err)
+	}
+}
+
+func TestAzureCLICredentialTimeout(t *testing.T) {
+	// exec so that killing the script kills sleep, which would otherwise hold its output open
+	path, _ := writeFakeCLI(t, "exec sleep 10\n")
+	cred, err := NewAzureCLICredential(&AzureCLICredentialOptions{CLIPath: path, Timeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err = cred.GetToken(context.Background(), testTokenOptions)
+	if err == nil || !strings.Contains(err.Error(), "didn't respond within") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("the timeout took %v", elapsed)
+	}
+}
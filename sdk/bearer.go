@@ -0,0 +1,492 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const (
+	// tokenRefreshWindow is how long before its expiry a cached token is proactively refreshed.
+	tokenRefreshWindow = 5 * time.Minute
+
+	// tokenExpiryBuffer is how long before its expiry a cached token is no longer used.
+	tokenExpiryBuffer = 30 * time.Second
+
+	// tokenRefreshRetryDelay is how long after a failed proactive refresh it's tried again.
+	tokenRefreshRetryDelay = 30 * time.Second
+
+	// tokenFetchTimeout is how long a token fetch can take before it's abandoned.
+	tokenFetchTimeout = time.Minute
+)
+
+// AccessToken is a bearer token along with the time it expires.
+type AccessToken struct {
+	Token     string
+	ExpiresOn time.Time
+}
+
+// TokenRequestOptions contains the parameters of a token request.
+type TokenRequestOptions struct {
+	// Scopes is the list of scopes the token is requested for, e.g. https://management.azure.com/.default.
+	Scopes []string
+
+	// TenantID is the tenant the token is requested from. Leave it empty to use the credential's tenant.
+	TenantID string
+
+	// Claims are additional claims required in the token, e.g. from a Continuous Access Evaluation challenge.
+	Claims string
+}
+
+// TokenProvider is implemented by types that can acquire access tokens, such as the credentials in this package.
+type TokenProvider interface {
+	// GetToken requests a new token for the scopes in opts.
+	GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error)
+}
+
+// BearerTokenOptions contains optional parameters for NewBearerTokenCredential.
+type BearerTokenOptions struct {
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope,
+	// unless EnableChallenge is set in which case requests are sent without a token until the service
+	// responds with a challenge.
+	Scopes []string
+
+	// EnableChallenge enables handling of bearer challenges. When a response has status 401 and a
+	// WWW-Authenticate Bearer challenge, a token is requested for the authority, resource or scope and
+	// claims in the challenge and the request is retried once. Challenges are only honoured if the host
+	// of every scope is the request's host or one of its parent domains, e.g. https://vault.azure.net
+	// for myvault.vault.azure.net. Subsequent requests to the same host use the tenant and scopes from
+	// the latest challenge. Services such as Key Vault require this, as do Continuous Access Evaluation
+	// claims challenges.
+	EnableChallenge bool
+}
+
+// NewBearerTokenCredential creates a Credential that authorizes requests with tokens obtained from p.
+// Tokens are cached and refreshed shortly before they expire, while the current token remains in use.
+// Concurrent requests that need a token wait for a single call to p.GetToken. Pass nil for options to
+// accept the default values.
+func NewBearerTokenCredential(p TokenProvider, options *BearerTokenOptions) Credential {
+	if options == nil {
+		options = &BearerTokenOptions{}
+	}
+	if options.EnableChallenge {
+		return &bearerTokenCredential{provider: p, challenge: true, defaults: TokenRequestOptions{Scopes: options.Scopes}}
+	}
+	return newBearerTokenCredential(p, options.Scopes)
+}
+
+// bearerTokenCredential is the credential policy factory used by all token credentials.
+type bearerTokenCredential struct {
+	provider  TokenProvider
+	challenge bool
+	cache     tokenCache
+
+	// defaults are the options used for hosts that haven't sent a challenge
+	defaults TokenRequestOptions
+
+	// lock protects challenged, the options learned from each host's latest challenge
+	lock       sync.Mutex
+	challenged map[string]TokenRequestOptions
+}
+
+// newBearerTokenCredential creates a bearerTokenCredential, requesting DefaultScope if scopes is empty.
+func newBearerTokenCredential(p TokenProvider, scopes []string) *bearerTokenCredential {
+	if len(scopes) == 0 {
+		scopes = []string{DefaultScope}
+	}
+	return &bearerTokenCredential{provider: p, defaults: TokenRequestOptions{Scopes: scopes}}
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*bearerTokenCredential) credentialMarker() {}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (b *bearerTokenCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		opts := b.options(req.URL.Host)
+		if len(opts.Scopes) > 0 {
+			tk, err := b.token(ctx, opts)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tk.Token))
+		}
+		resp, err := next.Do(ctx, req)
+		if !b.challenge {
+			return resp, err
+		}
+		raw := unauthorizedResponse(resp, err)
+		if raw == nil {
+			return resp, err
+		}
+		params, ok := parseBearerChallenge(raw.Header.Get("WWW-Authenticate"))
+		if !ok {
+			return resp, err
+		}
+		copts, cerr := challengeOptions(params, opts)
+		if cerr == nil {
+			cerr = verifyChallengeScopes(copts.Scopes, req.URL.Hostname())
+		}
+		if cerr != nil {
+			return resp, cerr
+		}
+		if copts.Claims == "" && copts.TenantID == opts.TenantID && strings.Join(copts.Scopes, " ") == strings.Join(opts.Scopes, " ") {
+			// the challenge doesn't ask for anything the rejected token didn't have
+			return resp, err
+		}
+		if rerr := req.RewindBody(); rerr != nil {
+			return resp, err
+		}
+		tk, terr := b.token(ctx, copts)
+		if terr != nil {
+			return resp, terr
+		}
+		b.lock.Lock()
+		if b.challenged == nil {
+			b.challenged = map[string]TokenRequestOptions{}
+		}
+		b.challenged[req.URL.Host] = TokenRequestOptions{Scopes: copts.Scopes, TenantID: copts.TenantID}
+		b.lock.Unlock()
+		// drain the body so the connection can be reused
+		io.Copy(ioutil.Discard, raw.Body)
+		raw.Body.Close()
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tk.Token))
+		return next.Do(ctx, req)
+	})
+}
+
+// options returns the token request options for requests to host.
+func (b *bearerTokenCredential) options(host string) TokenRequestOptions {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if opts, ok := b.challenged[host]; ok {
+		return opts
+	}
+	return b.defaults
+}
+
+// token returns a token for opts. Tokens with claims bypass the cached token, which lacks them,
+// and replace it so that subsequent requests include the claims.
+func (b *bearerTokenCredential) token(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	key := opts.TenantID + "|" + strings.Join(opts.Scopes, " ")
+	if opts.Claims != "" {
+		tk, err := b.provider.GetToken(ctx, opts)
+		if err != nil {
+			return AccessToken{}, err
+		}
+		b.cache.set(key, tk)
+		return tk, nil
+	}
+	return b.cache.getOrFetch(ctx, key, func(ctx context.Context) (AccessToken, error) {
+		return b.provider.GetToken(ctx, opts)
+	})
+}
+
+// unauthorizedResponse returns the HTTP response if the request was rejected with status 401.
+func unauthorizedResponse(resp pipeline.Response, err error) *http.Response {
+	raw := httpResponse(resp, err)
+	if raw == nil || raw.StatusCode != http.StatusUnauthorized {
+		return nil
+	}
+	return raw
+}
+
+// httpResponse returns the HTTP response returned by the next policy, if any. The response may be
+// returned as an error by responders that validate the status code, in which case err has a Response method.
+func httpResponse(resp pipeline.Response, err error) *http.Response {
+	var re interface{ Response() *http.Response }
+	if err != nil && errors.As(err, &re) {
+		return re.Response()
+	} else if err == nil && resp != nil {
+		return resp.Response()
+	}
+	return nil
+}
+
+// parseBearerChallenge returns the parameters of the Bearer challenge in a WWW-Authenticate header.
+// Parameter names are lower-cased. It returns false if there's no Bearer challenge.
+func parseBearerChallenge(header string) (map[string]string, bool) {
+	i := strings.Index(strings.ToLower(header), "bearer ")
+	if i < 0 {
+		return nil, false
+	}
+	s := header[i+len("bearer "):]
+	params := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " ,")
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		name := strings.ToLower(strings.TrimSpace(s[:eq]))
+		if strings.ContainsAny(name, " \t") {
+			// the start of another challenge
+			break
+		}
+		s = s[eq+1:]
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				return nil, false
+			}
+			value, s = s[1:end+1], s[end+2:]
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+		params[name] = value
+	}
+	return params, true
+}
+
+// challengeOptions returns the token request options for a challenge's parameters. The scopes and
+// tenant in current are used for anything the challenge doesn't specify.
+func challengeOptions(params map[string]string, current TokenRequestOptions) (TokenRequestOptions, error) {
+	opts := TokenRequestOptions{Scopes: current.Scopes, TenantID: current.TenantID}
+	if scope := params["scope"]; scope != "" {
+		opts.Scopes = strings.Fields(scope)
+	} else if resource := params["resource"]; resource != "" {
+		opts.Scopes = []string{strings.TrimSuffix(resource, "/") + "/.default"}
+	}
+	authority := params["authorization"]
+	if authority == "" {
+		authority = params["authorization_uri"]
+	}
+	if authority != "" {
+		u, err := url.Parse(authority)
+		if err != nil {
+			return opts, fmt.Errorf("invalid authority %q in challenge: %v", authority, err)
+		}
+		if tenantID := strings.Split(strings.Trim(u.Path, "/"), "/")[0]; tenantID != "" {
+			if !validTenantID(tenantID) {
+				return opts, fmt.Errorf("invalid tenant %q in challenge", tenantID)
+			}
+			opts.TenantID = tenantID
+		}
+	}
+	if claims := params["claims"]; claims != "" {
+		b, err := base64.StdEncoding.DecodeString(claims)
+		if err != nil {
+			if b, err = base64.RawStdEncoding.DecodeString(claims); err != nil {
+				return opts, fmt.Errorf("invalid claims in challenge: %v", err)
+			}
+		}
+		opts.Claims = string(b)
+	}
+	if len(opts.Scopes) == 0 {
+		return opts, fmt.Errorf("challenge doesn't specify a resource or scope")
+	}
+	return opts, nil
+}
+
+// verifyChallengeScopes returns an error unless the host of every scope is host or one of its parent
+// domains. Without this any server, including those in poller and pager URLs, could obtain tokens
+// for other services.
+func verifyChallengeScopes(scopes []string, host string) error {
+	host = strings.ToLower(host)
+	for _, scope := range scopes {
+		u, err := url.Parse(scope)
+		if err != nil || u.Hostname() == "" {
+			return fmt.Errorf("challenge scope %q isn't a URL", scope)
+		}
+		sh := strings.ToLower(u.Hostname())
+		// parent domains only apply to host names, not IP addresses
+		if host != sh && (net.ParseIP(host) != nil || !strings.HasSuffix(host, "."+sh)) {
+			return fmt.Errorf("challenge scope %q doesn't match the request's host %q", scope, host)
+		}
+	}
+	return nil
+}
+
+// validTenantID returns true if tenantID is a tenant ID or domain name.
+func validTenantID(tenantID string) bool {
+	for _, r := range tenantID {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return tenantID != "" && !strings.HasPrefix(tenantID, "-")
+}
+
+// tokenCache caches access tokens by tenant and scopes.
+type tokenCache struct {
+	lock    sync.Mutex
+	entries map[string]*cacheEntry
+	// fetchTimeout overrides tokenFetchTimeout when it's non-zero
+	fetchTimeout time.Duration
+}
+
+// cacheEntry is the cached token for a tenant and set of scopes.
+type cacheEntry struct {
+	token AccessToken
+	// refreshOn is when a proactive refresh of the token is started
+	refreshOn time.Time
+	// expiresOn is when the token stops being used, shortly before it expires
+	expiresOn time.Time
+	// fetch is the in-flight fetch, it's nil if there isn't one
+	fetch *tokenFetch
+}
+
+// tokenFetch is the result of fetching a token, it's valid once done is closed.
+type tokenFetch struct {
+	done  chan struct{}
+	token AccessToken
+	err   error
+}
+
+// usable returns true if the token can be used at time now.
+func (e *cacheEntry) usable(now time.Time) bool {
+	return e.token.Token != "" && now.Before(e.expiresOn)
+}
+
+// setToken replaces the entry's token. Short-lived tokens are refreshed halfway through their
+// lifetime and stop being used a tenth of their lifetime before they expire, so that they're
+// cached rather than fetched for every request.
+func (e *cacheEntry) setToken(tk AccessToken, now time.Time) {
+	lifetime := tk.ExpiresOn.Sub(now)
+	window, buffer := tokenRefreshWindow, tokenExpiryBuffer
+	if lifetime/2 < window {
+		window = lifetime / 2
+	}
+	if lifetime/10 < buffer {
+		buffer = lifetime / 10
+	}
+	e.token = tk
+	e.refreshOn = tk.ExpiresOn.Add(-window)
+	e.expiresOn = tk.ExpiresOn.Add(-buffer)
+}
+
+// getOrFetch returns the cached token for key, calling fetch to acquire a new one when the token
+// is due to be refreshed. Only one fetch per key is in flight at a time and its result is returned
+// to all callers waiting for it. While a usable token is being refreshed it continues to be returned.
+// fetch is passed a context that isn't cancelled with ctx, as other callers may be waiting for it;
+// cancelling ctx only stops waiting for the fetch. A fetch that doesn't complete within tokenFetchTimeout
+// fails so that a later call can start another.
+func (c *tokenCache) getOrFetch(ctx context.Context, key string, fetch func(context.Context) (AccessToken, error)) (AccessToken, error) {
+	c.lock.Lock()
+	if c.entries == nil {
+		c.entries = map[string]*cacheEntry{}
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[key] = e
+	}
+	now := time.Now()
+	if e.usable(now) && (e.fetch != nil || now.Before(e.refreshOn)) {
+		tk := e.token
+		c.lock.Unlock()
+		return tk, nil
+	}
+	f := e.fetch
+	if f == nil {
+		f = &tokenFetch{done: make(chan struct{})}
+		e.fetch = f
+		go c.runFetch(e, f, fetch)
+	}
+	if e.usable(now) {
+		// the token is being refreshed proactively, keep using it in the meantime
+		tk := e.token
+		c.lock.Unlock()
+		return tk, nil
+	}
+	c.lock.Unlock()
+	select {
+	case <-f.done:
+		return f.token, f.err
+	case <-ctx.Done():
+		return AccessToken{}, ctx.Err()
+	}
+}
+
+// runFetch calls fetch and completes f with the result, updating e if it succeeded.
+func (c *tokenCache) runFetch(e *cacheEntry, f *tokenFetch, fetch func(context.Context) (AccessToken, error)) {
+	timeout := c.fetchTimeout
+	if timeout == 0 {
+		timeout = tokenFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	type result struct {
+		tk  AccessToken
+		err error
+	}
+	// fetch runs in its own goroutine so that a provider ignoring ctx can't hold up the entry
+	ch := make(chan result, 1)
+	go func() {
+		tk, err := fetch(ctx)
+		ch <- result{tk: tk, err: err}
+	}()
+	var tk AccessToken
+	var err error
+	select {
+	case r := <-ch:
+		tk, err = r.tk, r.err
+	case <-ctx.Done():
+		err = fmt.Errorf("the token provider didn't respond within %v: %w", timeout, ctx.Err())
+	}
+	if err == nil && tk.Token == "" {
+		err = errors.New("the token provider returned an empty token")
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	if err == nil {
+		e.setToken(tk, now)
+	} else if e.usable(now) {
+		// the proactive refresh failed, keep using the current token and try
+		// again after a delay so that the token endpoint isn't called for every request
+		e.refreshOn = now.Add(tokenRefreshRetryDelay)
+	}
+	if err != nil {
+		f.err = err
+	} else {
+		f.token = tk
+	}
+	e.fetch = nil
+	close(f.done)
+}
+
+// set replaces the cached token for key.
+func (c *tokenCache) set(key string, tk AccessToken) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]*cacheEntry{}
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[key] = e
+	}
+	e.setToken(tk, time.Now())
+}
@@ -0,0 +1,363 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// recordingProvider returns tokens that encode the options they were requested with.
+type recordingProvider struct {
+	lock     sync.Mutex
+	requests []TokenRequestOptions
+}
+
+func (r *recordingProvider) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests = append(r.requests, opts)
+	return AccessToken{
+		Token:     opts.TenantID + ":" + strings.Join(opts.Scopes, ",") + ":" + opts.Claims,
+		ExpiresOn: time.Now().Add(time.Hour),
+	}, nil
+}
+
+func newTestRequest(t *testing.T, method string, rawurl string, body string) pipeline.Request {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := pipeline.NewRequest(method, *u, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func newChallengePipeline(p TokenProvider, scopes []string) pipeline.Pipeline {
+	cred := NewBearerTokenCredential(p, &BearerTokenOptions{EnableChallenge: true, Scopes: scopes})
+	return pipeline.NewPipeline([]pipeline.Factory{cred, pipeline.MethodFactoryMarker()}, pipeline.Options{})
+}
+
+func TestChallengeResource(t *testing.T) {
+	const want = "Bearer tid:https://127.0.0.1/.default:"
+	var auths, bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		auths = append(auths, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != want {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tid", resource="https://127.0.0.1"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	p := &recordingProvider{}
+	pl := newChallengePipeline(p, nil)
+	for i := 0; i < 2; i++ {
+		resp, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodPut, srv.URL, "body"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Response().StatusCode != http.StatusOK {
+			t.Fatalf("unexpected status %d", resp.Response().StatusCode)
+		}
+	}
+	if len(auths) != 3 || auths[0] != "" || auths[1] != want || auths[2] != want {
+		t.Fatalf("unexpected Authorization headers %q", auths)
+	}
+	if bodies[1] != "body" {
+		t.Fatalf("the retried request's body is %q", bodies[1])
+	}
+	if len(p.requests) != 1 {
+		t.Fatalf("expected one token request, got %d", len(p.requests))
+	}
+}
+
+func TestChallengeClaims(t *testing.T) {
+	const claims = `{"access_token":{"nbf":{"essential":true,"value":"1"}}}`
+	n := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if !strings.HasSuffix(r.Header.Get("Authorization"), claims) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="x", Bearer realm="", error="insufficient_claims", claims="`+
+				base64.StdEncoding.EncodeToString([]byte(claims))+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	p := &recordingProvider{}
+	pl := newChallengePipeline(p, []string{"https://127.0.0.1/.default"})
+	for i := 0; i < 2; i++ {
+		if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+	if len(p.requests) != 2 || p.requests[1].Claims != claims {
+		t.Fatalf("unexpected token requests %+v", p.requests)
+	}
+}
+
+func TestChallengeRejected(t *testing.T) {
+	for name, challenge := range map[string]string{
+		"other resource":         `Bearer resource="https://management.azure.com"`,
+		"other scope":            `Bearer scope="https://management.azure.com/.default"`,
+		"suffix isn't domain":    `Bearer resource="https://0.0.1"`,
+		"not a URL":              `Bearer scope="user.read"`,
+		"other tenant":           `Bearer authorization="https://login.microsoftonline.com/evil"`,
+		"tenant looks like flag": `Bearer authorization="https://login.microsoftonline.com/--help", resource="https://127.0.0.1"`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			n := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n++
+				w.Header().Set("WWW-Authenticate", challenge)
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer srv.Close()
+			p := &recordingProvider{}
+			// the configured scope is for another host, so a tenant change mustn't be honoured either
+			pl := newChallengePipeline(p, []string{"https://storage.azure.com/.default"})
+			if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err == nil {
+				t.Fatal("expected an error")
+			}
+			if n != 1 {
+				t.Fatalf("expected no retry, got %d requests", n)
+			}
+			for _, r := range p.requests {
+				if r.TenantID != "" || r.Scopes[0] != "https://storage.azure.com/.default" {
+					t.Fatalf("token requested for %+v", r)
+				}
+			}
+		})
+	}
+}
+
+func TestChallengeScopePerHost(t *testing.T) {
+	challenged := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer authorization="https://login.microsoftonline.com/tid", resource="https://127.0.0.1"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer challenged.Close()
+	var auth string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+	pl := newChallengePipeline(&recordingProvider{}, nil)
+	if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, challenged.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, other.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if auth != "" {
+		t.Fatalf("the token for another host was sent: %q", auth)
+	}
+}
+
+func TestVerifyChallengeScopes(t *testing.T) {
+	if err := verifyChallengeScopes([]string{"https://vault.azure.net/.default"}, "myvault.vault.azure.net"); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyChallengeScopes([]string{"https://vault.azure.net/.default"}, "myvaultvault.azure.net"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// countingProvider returns tokens valid for lifetime, each numbered with the call that fetched it.
+type countingProvider struct {
+	lock     sync.Mutex
+	calls    int
+	delay    time.Duration
+	lifetime time.Duration
+	fail     bool
+}
+
+func (c *countingProvider) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	c.lock.Lock()
+	c.calls++
+	n, fail := c.calls, c.fail
+	c.lock.Unlock()
+	select {
+	case <-time.After(c.delay):
+	case <-ctx.Done():
+		return AccessToken{}, ctx.Err()
+	}
+	if fail {
+		return AccessToken{}, errors.New("token request failed")
+	}
+	return AccessToken{Token: fmt.Sprintf("token%d", n), ExpiresOn: time.Now().Add(c.lifetime)}, nil
+}
+
+func (c *countingProvider) callCount() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.calls
+}
+
+func (c *countingProvider) setFail(fail bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.fail = fail
+}
+
+func getCachedToken(ctx context.Context, c *tokenCache, p TokenProvider) (AccessToken, error) {
+	return c.getOrFetch(ctx, "key", func(ctx context.Context) (AccessToken, error) {
+		return p.GetToken(ctx, TokenRequestOptions{})
+	})
+}
+
+func TestTokenCacheSingleFetch(t *testing.T) {
+	// a token shorter lived than tokenExpiryBuffer must still be returned to all callers
+	for _, lifetime := range []time.Duration{time.Hour, 10 * time.Second} {
+		p := &countingProvider{delay: 50 * time.Millisecond, lifetime: lifetime}
+		c := &tokenCache{}
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tk, err := getCachedToken(context.Background(), c, p)
+				if err != nil || tk.Token != "token1" {
+					t.Errorf("unexpected token %q, error %v", tk.Token, err)
+				}
+			}()
+		}
+		wg.Wait()
+		if tk, err := getCachedToken(context.Background(), c, p); err != nil || tk.Token != "token1" {
+			t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+		}
+		if calls := p.callCount(); calls != 1 {
+			t.Fatalf("expected one fetch for lifetime %v, got %d", lifetime, calls)
+		}
+	}
+}
+
+func TestTokenCacheCancelledCaller(t *testing.T) {
+	p := &countingProvider{delay: 100 * time.Millisecond, lifetime: time.Hour}
+	c := &tokenCache{}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() {
+		_, err := getCachedToken(ctx, c, p)
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	tk, err := getCachedToken(context.Background(), c, p)
+	if err != nil || tk.Token != "token1" {
+		t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+	}
+	if err := <-errs; err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenCacheProactiveRefresh(t *testing.T) {
+	// a two second token is refreshed after one second
+	p := &countingProvider{lifetime: 2 * time.Second}
+	c := &tokenCache{}
+	if tk, err := getCachedToken(context.Background(), c, p); err != nil || tk.Token != "token1" {
+		t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+	}
+	if tk, _ := getCachedToken(context.Background(), c, p); tk.Token != "token1" || p.callCount() != 1 {
+		t.Fatalf("the token was refreshed early")
+	}
+	time.Sleep(1100 * time.Millisecond)
+	// the current token is returned while it's refreshed
+	if tk, _ := getCachedToken(context.Background(), c, p); tk.Token != "token1" {
+		t.Fatalf("expected the current token, got %q", tk.Token)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if tk, _ := getCachedToken(context.Background(), c, p); tk.Token != "token2" {
+		t.Fatalf("expected the refreshed token, got %q", tk.Token)
+	}
+}
+
+func TestTokenCacheRefreshFailure(t *testing.T) {
+	p := &countingProvider{lifetime: 2 * time.Second}
+	c := &tokenCache{}
+	if _, err := getCachedToken(context.Background(), c, p); err != nil {
+		t.Fatal(err)
+	}
+	p.setFail(true)
+	time.Sleep(1100 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		if tk, err := getCachedToken(context.Background(), c, p); err != nil || tk.Token != "token1" {
+			t.Fatalf("expected the current token, got %q, error %v", tk.Token, err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	// the failed refresh isn't retried for every call
+	if calls := p.callCount(); calls != 2 {
+		t.Fatalf("expected two fetches, got %d", calls)
+	}
+	// once the token has expired the error is returned
+	time.Sleep(time.Second)
+	if _, err := getCachedToken(context.Background(), c, p); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// hangingProvider blocks its first call until release is closed, ignoring ctx, and succeeds after that.
+type hangingProvider struct {
+	lock    sync.Mutex
+	calls   int
+	release chan struct{}
+}
+
+func (h *hangingProvider) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	h.lock.Lock()
+	h.calls++
+	n := h.calls
+	h.lock.Unlock()
+	if n == 1 {
+		<-h.release
+	}
+	return AccessToken{Token: fmt.Sprintf("token%d", n), ExpiresOn: time.Now().Add(time.Hour)}, nil
+}
+
+func TestTokenCacheFetchTimeout(t *testing.T) {
+	p := &hangingProvider{release: make(chan struct{})}
+	defer close(p.release)
+	c := &tokenCache{fetchTimeout: 50 * time.Millisecond}
+	if _, err := getCachedToken(context.Background(), c, p); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	// the hung fetch doesn't block a new one
+	tk, err := getCachedToken(context.Background(), c, p)
+	if err != nil || tk.Token != "token2" {
+		t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+	}
+}
@@ -0,0 +1,191 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// ChainedCredentialError is returned when none of the credentials in a ChainedCredential could provide a token.
+type ChainedCredentialError struct {
+	// Errors contains the error returned by each credential, in the order they were tried.
+	Errors []error
+}
+
+// Error implements the error interface's Error method.
+func (e *ChainedCredentialError) Error() string {
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "none of the %d credentials in the chain provided a token:", len(e.Errors))
+	for _, err := range e.Errors {
+		fmt.Fprintf(b, "\n\t%v", err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors returned by the credentials, for use with errors.Is and errors.As.
+func (e *ChainedCredentialError) Unwrap() []error {
+	return e.Errors
+}
+
+// ChainedCredentialOptions contains optional parameters for NewChainedCredential.
+type ChainedCredentialOptions struct {
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+}
+
+// ChainedCredential tries a list of credentials in order until one provides a token. Only credentials that
+// are unavailable, i.e. that return a *CredentialUnavailableError, are skipped; any other error, such as an
+// *AuthenticationFailedError from a misconfigured credential, is returned rather than trying the next one.
+// The first credential that succeeds is used for all subsequent token requests.
+type ChainedCredential struct {
+	sources  []TokenProvider
+	bearer   *bearerTokenCredential
+	lock     sync.Mutex
+	selected TokenProvider
+}
+
+// NewChainedCredential creates a ChainedCredential from sources, which must also implement TokenProvider
+// like the credentials in this package. Pass nil for options to accept the default values.
+func NewChainedCredential(sources []Credential, options *ChainedCredentialOptions) (*ChainedCredential, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("sources can't be empty")
+	}
+	c := &ChainedCredential{}
+	for i, s := range sources {
+		tp, ok := s.(TokenProvider)
+		if !ok {
+			return nil, fmt.Errorf("sources[%d] of type %T can't be used in a chain", i, s)
+		}
+		c.sources = append(c.sources, tp)
+	}
+	if options == nil {
+		options = &ChainedCredentialOptions{}
+	}
+	c.bearer = newBearerTokenCredential(c, options.Scopes)
+	return c, nil
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*ChainedCredential) credentialMarker() {}
+
+// GetToken requests a new token for the requested scopes from the first available credential in the chain.
+// If none are available, the returned error is a *ChainedCredentialError.
+func (c *ChainedCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	c.lock.Lock()
+	selected := c.selected
+	c.lock.Unlock()
+	if selected != nil {
+		return selected.GetToken(ctx, opts)
+	}
+	var errs []error
+	for _, s := range c.sources {
+		tk, err := s.GetToken(ctx, opts)
+		if err == nil {
+			c.lock.Lock()
+			if c.selected == nil {
+				c.selected = s
+			}
+			c.lock.Unlock()
+			return tk, nil
+		}
+		var unavailable *CredentialUnavailableError
+		if !errors.As(err, &unavailable) {
+			return AccessToken{}, err
+		}
+		errs = append(errs, err)
+	}
+	return AccessToken{}, &ChainedCredentialError{Errors: errs}
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *ChainedCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
+
+// DefaultCredentialOptions contains optional parameters for NewDefaultCredential.
+type DefaultCredentialOptions struct {
+	// AuthorityHost is the Azure Active Directory authority host. Defaults to DefaultAuthorityHost.
+	AuthorityHost string
+
+	// HTTPClient is the client used to send token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// ManagedIdentityClientID is the client ID of a user-assigned managed identity.
+	// Leave it empty to use the system-assigned identity.
+	ManagedIdentityClientID string
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+}
+
+// NewDefaultCredential creates a ChainedCredential suitable for most applications. It tries, in order,
+// an EnvironmentCredential, a ManagedIdentityCredential and an AzureCLICredential. The managed identity
+// credential gives up after a second if the Instance Metadata Service doesn't respond, so that the chain
+// moves on quickly when not running on Azure. Pass nil for options to accept the default values.
+func NewDefaultCredential(options *DefaultCredentialOptions) (*ChainedCredential, error) {
+	if options == nil {
+		options = &DefaultCredentialOptions{}
+	}
+	var sources []Credential
+	if env, err := NewEnvironmentCredential(&EnvironmentCredentialOptions{
+		AuthorityHost: options.AuthorityHost,
+		HTTPClient:    options.HTTPClient,
+	}); err == nil {
+		sources = append(sources, env)
+	} else {
+		sources = append(sources, &unavailableCredential{err: err})
+	}
+	if mi, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{
+		ClientID:     options.ManagedIdentityClientID,
+		HTTPClient:   options.HTTPClient,
+		ProbeTimeout: defaultIMDSProbeTimeout,
+	}); err == nil {
+		sources = append(sources, mi)
+	} else {
+		sources = append(sources, &unavailableCredential{err: err})
+	}
+	if cli, err := NewAzureCLICredential(nil); err == nil {
+		sources = append(sources, cli)
+	} else {
+		sources = append(sources, &unavailableCredential{err: err})
+	}
+	return NewChainedCredential(sources, &ChainedCredentialOptions{Scopes: options.Scopes})
+}
+
+// unavailableCredential stands in for a credential that couldn't be created so
+// that the reason is included in the error returned by a ChainedCredential.
+type unavailableCredential struct {
+	err error
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*unavailableCredential) credentialMarker() {}
+
+// GetToken always returns the error that prevented the credential from being created.
+func (c *unavailableCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	return AccessToken{}, c.err
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *unavailableCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return newBearerTokenCredential(c, nil).New(next, po)
+}
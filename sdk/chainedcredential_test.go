@@ -0,0 +1,96 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// staticProvider returns the same token or error for every request.
+type staticProvider struct {
+	token AccessToken
+	err   error
+	calls int
+}
+
+func (*staticProvider) credentialMarker() {}
+
+func (s *staticProvider) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	s.calls++
+	return s.token, s.err
+}
+
+func (s *staticProvider) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return newBearerTokenCredential(s, nil).New(next, po)
+}
+
+var testTokenOptions = TokenRequestOptions{Scopes: []string{DefaultScope}}
+
+func TestChainedCredentialSkipsUnavailable(t *testing.T) {
+	unavailable := &staticProvider{err: &CredentialUnavailableError{CredentialType: "A", Message: "not configured"}}
+	available := &staticProvider{token: AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}}
+	c, err := NewChainedCredential([]Credential{unavailable, available}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		tk, err := c.GetToken(context.Background(), testTokenOptions)
+		if err != nil || tk.Token != "token" {
+			t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+		}
+	}
+	// the credential that succeeded is used for subsequent requests
+	if unavailable.calls != 1 || available.calls != 2 {
+		t.Fatalf("unexpected calls %d, %d", unavailable.calls, available.calls)
+	}
+}
+
+func TestChainedCredentialStopsOnAuthenticationFailure(t *testing.T) {
+	failed := &staticProvider{err: &AuthenticationFailedError{StatusCode: http.StatusUnauthorized, Code: "invalid_client"}}
+	next := &staticProvider{token: AccessToken{Token: "token", ExpiresOn: time.Now().Add(time.Hour)}}
+	c, err := NewChainedCredential([]Credential{failed, next}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetToken(context.Background(), testTokenOptions)
+	var authErr *AuthenticationFailedError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected an *AuthenticationFailedError, got %v", err)
+	}
+	if next.calls != 0 {
+		t.Fatal("the next credential was tried")
+	}
+}
+
+func TestChainedCredentialAllUnavailable(t *testing.T) {
+	c, err := NewChainedCredential([]Credential{
+		&staticProvider{err: &CredentialUnavailableError{CredentialType: "A", Message: "a"}},
+		&staticProvider{err: &CredentialUnavailableError{CredentialType: "B", Message: "b"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetToken(context.Background(), testTokenOptions)
+	var chainErr *ChainedCredentialError
+	if !errors.As(err, &chainErr) || len(chainErr.Errors) != 2 {
+		t.Fatalf("expected a *ChainedCredentialError with 2 errors, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// clientAssertionLifetime is how long a client assertion is valid for after it's created.
+const clientAssertionLifetime = 10 * time.Minute
+
+// ClientCertificateCredentialOptions contains optional parameters for NewClientCertificateCredential.
+type ClientCertificateCredentialOptions struct {
+	// AuthorityHost is the Azure Active Directory authority host. Defaults to DefaultAuthorityHost.
+	AuthorityHost string
+
+	// HTTPClient is the client used to send token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+
+	// SendCertificateChain includes the certificate chain in the x5c header of client assertions.
+	// This is required for subject name/issuer authentication.
+	SendCertificateChain bool
+}
+
+// ClientCertificateCredential authenticates a service principal with a certificate. Each token
+// request is authorized with a client assertion, a JWT signed with the certificate's private key.
+type ClientCertificateCredential struct {
+	client     *aadClient
+	clientID   string
+	certs      []*x509.Certificate
+	key        *rsa.PrivateKey
+	thumbprint string
+	sendChain  bool
+	bearer     *bearerTokenCredential
+}
+
+// NewClientCertificateCredential creates a ClientCertificateCredential for the service principal clientID in
+// tenant tenantID. certs must contain the certificate for key, an RSA private key, optionally followed by the
+// rest of its chain. ParseCertificates can load both from PEM or PKCS#12 data. Pass nil for options to accept
+// the default values.
+func NewClientCertificateCredential(tenantID string, clientID string, certs []*x509.Certificate, key crypto.PrivateKey, options *ClientCertificateCredentialOptions) (*ClientCertificateCredential, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("clientID can't be empty")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T, only RSA keys are supported", key)
+	}
+	// the certificate for the key must be the first in the chain
+	var leaf *x509.Certificate
+	chain := []*x509.Certificate{nil}
+	for _, cert := range certs {
+		if pub, ok := cert.PublicKey.(*rsa.PublicKey); ok && leaf == nil && pub.Equal(&rsaKey.PublicKey) {
+			leaf = cert
+			continue
+		}
+		chain = append(chain, cert)
+	}
+	if leaf == nil {
+		return nil, fmt.Errorf("certs doesn't contain a certificate for the private key")
+	}
+	chain[0] = leaf
+	if options == nil {
+		options = &ClientCertificateCredentialOptions{}
+	}
+	c, err := newAADClient(tenantID, options.AuthorityHost, options.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint := sha1.Sum(chain[0].Raw)
+	cred := &ClientCertificateCredential{
+		client:     c,
+		clientID:   clientID,
+		certs:      chain,
+		key:        rsaKey,
+		thumbprint: base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+		sendChain:  options.SendCertificateChain,
+	}
+	cred.bearer = newBearerTokenCredential(cred, options.Scopes)
+	return cred, nil
+}
+
+// NewClientCertificateCredentialFromFile creates a ClientCertificateCredential from a PEM or PKCS#12 file
+// containing the certificate and its private key. password is only used for encrypted files.
+func NewClientCertificateCredentialFromFile(tenantID string, clientID string, certPath string, password string, options *ClientCertificateCredentialOptions) (*ClientCertificateCredential, error) {
+	data, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return nil, err
+	}
+	certs, key, err := ParseCertificates(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %v", certPath, err)
+	}
+	return NewClientCertificateCredential(tenantID, clientID, certs, key, options)
+}
+
+// ParseCertificates loads certificates and a private key from PEM or PKCS#12 data. password is used to
+// decrypt PKCS#12 data and encrypted private keys, pass an empty string if they aren't encrypted. PKCS#12
+// data may be encrypted with PBES2 (AES), as exported by default by OpenSSL 3 and current versions of
+// Windows, or the legacy 3DES and RC2 schemes. Encrypted PEM private keys may be PKCS#8 ("ENCRYPTED
+// PRIVATE KEY") or, for compatibility with older tools, use the insecure legacy Proc-Type encryption.
+func ParseCertificates(data []byte, password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to decode PKCS#12 data: %v", err)
+		}
+		return append([]*x509.Certificate{leaf}, chain...), key, nil
+	}
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		var k crypto.PrivateKey
+		var err error
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			certs = append(certs, cert)
+			continue
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+			b := block.Bytes
+			// legacy encrypted PEM is insecure but still produced by many tools
+			if x509.IsEncryptedPEMBlock(block) {
+				if b, err = x509.DecryptPEMBlock(block, []byte(password)); err != nil {
+					return nil, nil, fmt.Errorf("failed to decrypt private key: %v", err)
+				}
+			}
+			if k, err = parsePrivateKey(b); err != nil {
+				return nil, nil, err
+			}
+		case "ENCRYPTED PRIVATE KEY":
+			if password == "" {
+				return nil, nil, fmt.Errorf("the private key is encrypted but no password was provided")
+			}
+			if k, err = pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(password)); err != nil {
+				return nil, nil, fmt.Errorf("failed to decrypt private key: %v", err)
+			}
+		default:
+			continue
+		}
+		if key != nil {
+			return nil, nil, fmt.Errorf("found more than one private key")
+		}
+		key = k
+	}
+	if len(certs) == 0 {
+		return nil, nil, fmt.Errorf("no certificates found")
+	}
+	if key == nil {
+		return nil, nil, fmt.Errorf("no private key found")
+	}
+	return certs, key, nil
+}
+
+// parsePrivateKey parses a PKCS#1, PKCS#8 or SEC 1 DER encoded private key.
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("failed to parse private key")
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*ClientCertificateCredential) credentialMarker() {}
+
+// GetToken requests a new token for the requested scopes, authorized with a new client assertion.
+func (c *ClientCertificateCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	assertion, err := c.createAssertion(c.client.tokenEndpoint(opts))
+	if err != nil {
+		return AccessToken{}, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientID)
+	form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+	form.Set("client_assertion", assertion)
+	return c.client.requestToken(ctx, opts, form)
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *ClientCertificateCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
+
+// createAssertion creates a client assertion for the token endpoint aud signed with RS256.
+func (c *ClientCertificateCredential) createAssertion(aud string) (string, error) {
+	header := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": c.thumbprint,
+	}
+	if c.sendChain {
+		x5c := make([]string, len(c.certs))
+		for i, cert := range c.certs {
+			x5c[i] = base64.StdEncoding.EncodeToString(cert.Raw)
+		}
+		header["x5c"] = x5c
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"aud": aud,
+		"iss": c.clientID,
+		"sub": c.clientID,
+		"jti": fmt.Sprintf("%x", jti),
+		"nbf": now.Unix(),
+		"exp": now.Add(clientAssertionLifetime).Unix(),
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cl, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(cl)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
@@ -0,0 +1,147 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// The files in testdata contain the same self-signed certificate and RSA key, created with OpenSSL 3:
+//
+//	openssl req -x509 -newkey rsa:2048 -nodes -keyout key.pem -out cert.pem -days 36500 -subj "/CN=test"
+//	openssl pkcs8 -topk8 -v2 aes-256-cbc -in key.pem -passout pass:password -out enc.pem
+//	openssl rsa -in key.pem -aes256 -traditional -passout pass:password -out legacy.pem
+//	openssl pkcs12 -export -in cert.pem -inkey key.pem -passout pass:password -out certificate-aes.pfx
+//	openssl pkcs12 -export -in cert.pem -inkey key.pem -passout pass:password \
+//		-certpbe PBE-SHA1-3DES -keypbe PBE-SHA1-3DES -macalg sha1 -out certificate-3des.pfx
+//
+// certificate.pem, certificate-pkcs8-encrypted.pem and certificate-legacy-encrypted.pem
+// are cert.pem followed by key.pem, enc.pem and legacy.pem respectively.
+const testCertificatePassword = "password"
+
+func readTestCertificate(t *testing.T, name string, password string) ([]*x509.Certificate, crypto.PrivateKey) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, key, err := ParseCertificates(data, password)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return certs, key
+}
+
+func TestParseCertificates(t *testing.T) {
+	for name, password := range map[string]string{
+		"certificate.pem":                  "",
+		"certificate-pkcs8-encrypted.pem":  testCertificatePassword,
+		"certificate-legacy-encrypted.pem": testCertificatePassword,
+		"certificate-aes.pfx":              testCertificatePassword,
+		"certificate-3des.pfx":             testCertificatePassword,
+	} {
+		t.Run(name, func(t *testing.T) {
+			certs, key := readTestCertificate(t, name, password)
+			if len(certs) != 1 || certs[0].Subject.CommonName != "test" {
+				t.Fatalf("unexpected certificates %v", certs)
+			}
+			rsaKey, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				t.Fatalf("unexpected key type %T", key)
+			}
+			if !rsaKey.PublicKey.Equal(certs[0].PublicKey) {
+				t.Fatal("the key doesn't match the certificate")
+			}
+		})
+	}
+}
+
+func TestParseCertificatesWrongPassword(t *testing.T) {
+	for _, name := range []string{
+		"certificate-pkcs8-encrypted.pem",
+		"certificate-legacy-encrypted.pem",
+		"certificate-aes.pfx",
+		"certificate-3des.pfx",
+	} {
+		for _, password := range []string{"", "wrong"} {
+			data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := ParseCertificates(data, password); err == nil {
+				t.Errorf("%s: expected an error for password %q", name, password)
+			}
+		}
+	}
+}
+
+func TestClientCertificateCredential(t *testing.T) {
+	certs, key := readTestCertificate(t, "certificate-aes.pfx", testCertificatePassword)
+	var tokenURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		parts := strings.Split(r.Form.Get("client_assertion"), ".")
+		if len(parts) != 3 {
+			// t.Fatalf can't be called from the handler's goroutine
+			t.Errorf("malformed assertion %q", r.Form.Get("client_assertion"))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.(*rsa.PrivateKey).PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Errorf("invalid signature: %v", err)
+		}
+		var header, claims map[string]interface{}
+		b, _ := base64.RawURLEncoding.DecodeString(parts[0])
+		json.Unmarshal(b, &header)
+		b, _ = base64.RawURLEncoding.DecodeString(parts[1])
+		json.Unmarshal(b, &claims)
+		if header["x5t"] == nil || header["x5c"] == nil {
+			t.Errorf("unexpected header %v", header)
+		}
+		if claims["aud"] != tokenURL || claims["iss"] != "client" || claims["sub"] != "client" {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		fmt.Fprint(w, `{"access_token":"token","expires_in":3600}`)
+	}))
+	defer srv.Close()
+	tokenURL = srv.URL + "/tenant/oauth2/v2.0/token"
+	cred, err := NewClientCertificateCredential("tenant", "client", certs, key, &ClientCertificateCredentialOptions{
+		AuthorityHost:        srv.URL,
+		SendCertificateChain: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk, err := cred.GetToken(context.Background(), testTokenOptions)
+	if err != nil || tk.Token != "token" {
+		t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+	}
+}
@@ -0,0 +1,80 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// ClientSecretCredentialOptions contains optional parameters for NewClientSecretCredential.
+type ClientSecretCredentialOptions struct {
+	// AuthorityHost is the Azure Active Directory authority host. Defaults to DefaultAuthorityHost.
+	AuthorityHost string
+
+	// HTTPClient is the client used to send token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+}
+
+// ClientSecretCredential authenticates a service principal with a client secret using
+// the OAuth2 client credentials flow.
+type ClientSecretCredential struct {
+	client       *aadClient
+	clientID     string
+	clientSecret string
+	bearer       *bearerTokenCredential
+}
+
+// NewClientSecretCredential creates a ClientSecretCredential for the service principal clientID in tenant tenantID.
+// Pass nil for options to accept the default values.
+func NewClientSecretCredential(tenantID string, clientID string, clientSecret string, options *ClientSecretCredentialOptions) (*ClientSecretCredential, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("clientID can't be empty")
+	}
+	if options == nil {
+		options = &ClientSecretCredentialOptions{}
+	}
+	c, err := newAADClient(tenantID, options.AuthorityHost, options.HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+	cred := &ClientSecretCredential{client: c, clientID: clientID, clientSecret: clientSecret}
+	cred.bearer = newBearerTokenCredential(cred, options.Scopes)
+	return cred, nil
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*ClientSecretCredential) credentialMarker() {}
+
+// GetToken requests a new token for the requested scopes.
+func (c *ClientSecretCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientID)
+	form.Set("client_secret", c.clientSecret)
+	return c.client.requestToken(ctx, opts, form)
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *ClientSecretCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
@@ -0,0 +1,93 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+func TestClientSecretCredential(t *testing.T) {
+	var tokenRequests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		if r.Method != http.MethodPost || r.URL.Path != "/tenant/oauth2/v2.0/token" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		for k, v := range map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     "client",
+			"client_secret": "secret",
+			"scope":         DefaultScope,
+		} {
+			if got := r.PostForm.Get(k); got != v {
+				t.Errorf("expected %s %q, got %q", k, v, got)
+			}
+		}
+		fmt.Fprint(w, `{"token_type":"Bearer","access_token":"token","expires_in":"3600"}`)
+	}))
+	defer srv.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("unexpected Authorization header %q", auth)
+		}
+	}))
+	defer api.Close()
+	cred, err := NewClientSecretCredential("tenant", "client", "secret", &ClientSecretCredentialOptions{AuthorityHost: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pipeline.NewPipeline([]pipeline.Factory{cred, pipeline.MethodFactoryMarker()}, pipeline.Options{})
+	for i := 0; i < 2; i++ {
+		resp, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, api.URL, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Response().Body.Close()
+	}
+	// the second request uses the cached token
+	if n := atomic.LoadInt32(&tokenRequests); n != 1 {
+		t.Fatalf("expected one token request, got %d", n)
+	}
+}
+
+func TestClientSecretCredentialAuthenticationFailed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client","error_description":"AADSTS7000215: Invalid client secret provided."}`)
+	}))
+	defer srv.Close()
+	cred, err := NewClientSecretCredential("tenant", "client", "wrong", &ClientSecretCredentialOptions{AuthorityHost: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = cred.GetToken(context.Background(), testTokenOptions)
+	authErr, ok := err.(*AuthenticationFailedError)
+	if !ok {
+		t.Fatalf("expected an AuthenticationFailedError, got %v", err)
+	}
+	if authErr.StatusCode != http.StatusUnauthorized || authErr.Code != "invalid_client" || authErr.Description == "" {
+		t.Fatalf("unexpected error %+v", authErr)
+	}
+}
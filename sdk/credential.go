@@ -17,7 +17,6 @@ package sdk
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,6 +30,28 @@ type Credential interface {
 	credentialMarker()
 }
 
+// NewAnonymousCredential creates a Credential that doesn't authorize requests, for use with public endpoints
+// and local emulators. Pipelines created with it still have the user-agent, retry and other policies.
+func NewAnonymousCredential() Credential {
+	return anonymousCredential
+}
+
+// anonymousCredential is shared by all callers of NewAnonymousCredential as it has no state.
+var anonymousCredential = &anonymousCredentialPolicyFactory{}
+
+// anonymousCredentialPolicyFactory is the credential's policy factory.
+type anonymousCredentialPolicyFactory struct{}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*anonymousCredentialPolicyFactory) credentialMarker() {}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (*anonymousCredentialPolicyFactory) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		return next.Do(ctx, req)
+	})
+}
+
 // TokenCredential represents a token credential (which is also a pipeline.Factory).
 type TokenCredential interface {
 	Credential
@@ -38,46 +59,49 @@ type TokenCredential interface {
 	SetToken(newToken string)
 }
 
+// RefreshingTokenCredential is a TokenCredential whose token is refreshed by a user-provided callback.
+// Call Close when the credential is no longer needed to stop its refresh timer.
+type RefreshingTokenCredential interface {
+	TokenCredential
+
+	// Close stops refreshing the token. If a refresh is in progress, Close waits for it to finish.
+	// Close must not be called from the tokenRefresher callback.
+	Close()
+
+	// Restart resumes refreshing after Close, or after the tokenRefresher callback returned zero,
+	// calling the tokenRefresher callback immediately. It does nothing if the credential is still
+	// refreshing. Restart must not be called from the tokenRefresher callback.
+	Restart()
+}
+
 // NewTokenCredential creates a token credential for use with role-based access control (RBAC) access to Azure Storage
 // resources. You initialize the TokenCredential with an initial token value. If you pass a non-nil value for
 // tokenRefresher, then the function you pass will be called immediately (so it can refresh and change the
 // TokenCredential's token value by calling SetToken; your tokenRefresher function must return a time.Duration
 // indicating how long the TokenCredential object should wait before calling your tokenRefresher function again.
+// If tokenRefresher is non-nil the returned credential is a RefreshingTokenCredential which must be closed,
+// prefer calling NewRefreshingTokenCredential in that case.
 func NewTokenCredential(initialToken string, tokenRefresher func(credential TokenCredential) time.Duration) TokenCredential {
+	if tokenRefresher != nil {
+		return NewRefreshingTokenCredential(initialToken, tokenRefresher)
+	}
+	// If no callback specified, return the simple tokenCredential
 	tc := &tokenCredential{}
 	tc.SetToken(initialToken) // We dont' set it above to guarantee atomicity
-	if tokenRefresher == nil {
-		return tc // If no callback specified, return the simple tokenCredential
-	}
-
-	tcwr := &tokenCredentialWithRefresh{token: tc}
-	tcwr.token.startRefresh(tokenRefresher)
-	runtime.SetFinalizer(tcwr, func(deadTC *tokenCredentialWithRefresh) {
-		deadTC.token.stopRefresh()
-		deadTC.token = nil //  Sanity (not really required)
-	})
-	return tcwr
-}
-
-// tokenCredentialWithRefresh is a wrapper over a token credential.
-// When this wrapper object gets GC'd, it stops the tokenCredential's timer
-// which allows the tokenCredential object to also be GC'd.
-type tokenCredentialWithRefresh struct {
-	token *tokenCredential
+	return tc
 }
 
-// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
-func (*tokenCredentialWithRefresh) credentialMarker() {}
-
-// Token returns the current token value
-func (f *tokenCredentialWithRefresh) Token() string { return f.token.Token() }
-
-// SetToken changes the current token value
-func (f *tokenCredentialWithRefresh) SetToken(token string) { f.token.SetToken(token) }
-
-// New satisfies pipeline.Factory's New method creating a pipeline policy object.
-func (f *tokenCredentialWithRefresh) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
-	return f.token.New(next, po)
+// NewRefreshingTokenCredential creates a token credential initialized with initialToken whose token is refreshed
+// by tokenRefresher. tokenRefresher is called immediately and then again after the time.Duration it returns has
+// elapsed; return zero to stop refreshing. Call Close when the credential is no longer needed.
+func NewRefreshingTokenCredential(initialToken string, tokenRefresher func(credential TokenCredential) time.Duration) RefreshingTokenCredential {
+	if tokenRefresher == nil {
+		panic("tokenRefresher can't be nil")
+	}
+	tc := &tokenCredential{}
+	tc.SetToken(initialToken)
+	tc.startRefresh(tokenRefresher)
+	return tc
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -91,6 +115,10 @@ type tokenCredential struct {
 	tokenRefresher func(c TokenCredential) time.Duration
 	lock           sync.Mutex
 	stopped        bool
+	generation     int // incremented by Restart so that timers started before Close are ignored
+	refreshing     sync.WaitGroup
+	// lifecycle serializes Close and Restart so that Restart can't start a refresh while Close waits
+	lifecycle sync.Mutex
 }
 
 // credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
@@ -105,33 +133,70 @@ func (tc *tokenCredential) SetToken(token string) { tc.token.Store(token) }
 // startRefresh calls refresh which immediately calls tokenRefresher
 // and then starts a timer to call tokenRefresher in the future.
 func (tc *tokenCredential) startRefresh(tokenRefresher func(c TokenCredential) time.Duration) {
+	tc.lock.Lock()
 	tc.tokenRefresher = tokenRefresher
-	tc.stopped = false // In case user calls StartRefresh, StopRefresh, & then StartRefresh again
-	tc.refresh()
+	gen := tc.generation
+	tc.lock.Unlock()
+	tc.refresh(gen)
 }
 
 // refresh calls the user's tokenRefresher so they can refresh the token (by
 // calling SetToken) and then starts another time (based on the returned duration)
 // in order to refresh the token again in the future.
-func (tc *tokenCredential) refresh() {
+func (tc *tokenCredential) refresh(gen int) {
+	tc.lock.Lock()
+	if tc.stopped || gen != tc.generation {
+		tc.lock.Unlock()
+		return
+	}
+	// Close waits for this refresh. Add can't race with Wait: it only happens while not stopped,
+	// Close sets stopped before waiting and Restart can't clear it until Close has returned.
+	tc.refreshing.Add(1)
+	defer tc.refreshing.Done()
+	tc.lock.Unlock()
+
 	d := tc.tokenRefresher(tc) // Invoke the user's refresh callback outside of the lock
 	tc.lock.Lock()
-	if !tc.stopped && d > 0 {
-		tc.timer = time.AfterFunc(d, tc.refresh)
+	if !tc.stopped && gen == tc.generation {
+		if d > 0 {
+			tc.timer = time.AfterFunc(d, func() { tc.refresh(gen) })
+		} else {
+			// the refresher asked to stop, Restart can resume refreshing
+			tc.stopped = true
+		}
 	}
 	tc.lock.Unlock()
 }
 
-// stopRefresh stops any pending timer and sets stopped field to true to prevent
-// any new timer from starting.
-// NOTE: Stopping the timer allows the GC to destroy the tokenCredential object.
-func (tc *tokenCredential) stopRefresh() {
+// Close stops any pending timer and sets stopped field to true to prevent
+// any new timer from starting, then waits for an in-flight refresh to finish.
+func (tc *tokenCredential) Close() {
+	tc.lifecycle.Lock()
+	defer tc.lifecycle.Unlock()
 	tc.lock.Lock()
 	tc.stopped = true
 	if tc.timer != nil {
 		tc.timer.Stop()
+		tc.timer = nil
+	}
+	tc.lock.Unlock()
+	tc.refreshing.Wait()
+}
+
+// Restart resumes refreshing the token after Close.
+func (tc *tokenCredential) Restart() {
+	tc.lifecycle.Lock()
+	defer tc.lifecycle.Unlock()
+	tc.lock.Lock()
+	if tc.tokenRefresher == nil || !tc.stopped {
+		tc.lock.Unlock()
+		return
 	}
+	tc.stopped = false
+	tc.generation++
+	gen := tc.generation
 	tc.lock.Unlock()
+	tc.refresh(gen)
 }
 
 func (tc *tokenCredential) New(next pipeline.Policy, o *pipeline.PolicyOptions) pipeline.Policy {
@@ -0,0 +1,116 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshingTokenCredentialCloseWaits(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	var calls int32
+	c := NewRefreshingTokenCredential("initial", func(tc TokenCredential) time.Duration {
+		if atomic.AddInt32(&calls, 1) == 2 {
+			// block the first timer-driven refresh
+			close(entered)
+			<-release
+			tc.SetToken("refreshed")
+		}
+		return time.Millisecond
+	})
+	<-entered
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a refresh was in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+	if c.Token() != "refreshed" {
+		t.Fatalf("unexpected token %q", c.Token())
+	}
+	n := atomic.LoadInt32(&calls)
+	time.Sleep(20 * time.Millisecond)
+	if atomic.LoadInt32(&calls) != n {
+		t.Fatal("the token was refreshed after Close")
+	}
+}
+
+func TestRefreshingTokenCredentialRestart(t *testing.T) {
+	var calls int32
+	c := NewRefreshingTokenCredential("initial", func(tc TokenCredential) time.Duration {
+		atomic.AddInt32(&calls, 1)
+		// stop refreshing after the first call
+		return 0
+	})
+	c.Restart()
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("expected Restart to refresh after the refresher stopped, got %d calls", n)
+	}
+	c.Close()
+	c.Restart()
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("expected Restart to refresh after Close, got %d calls", n)
+	}
+}
+
+func TestRefreshingTokenCredentialConcurrentCloseRestart(t *testing.T) {
+	var active int32
+	c := NewRefreshingTokenCredential("initial", func(tc TokenCredential) time.Duration {
+		atomic.AddInt32(&active, 1)
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&active, -1)
+		return time.Millisecond
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Close()
+		}()
+		go func() {
+			defer wg.Done()
+			c.Restart()
+		}()
+	}
+	wg.Wait()
+	c.Close()
+	if n := atomic.LoadInt32(&active); n != 0 {
+		t.Fatalf("%d refreshes running after Close", n)
+	}
+}
+
+func TestNewTokenCredential(t *testing.T) {
+	if c := NewTokenCredential("token", nil); c.Token() != "token" {
+		t.Fatalf("unexpected token %q", c.Token())
+	}
+	c, ok := NewTokenCredential("token", func(TokenCredential) time.Duration { return 0 }).(RefreshingTokenCredential)
+	if !ok {
+		t.Fatal("expected a RefreshingTokenCredential")
+	}
+	c.Close()
+	if c.Token() != "token" {
+		t.Fatalf("unexpected token %q", c.Token())
+	}
+}
@@ -0,0 +1,108 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// EnvironmentCredentialOptions contains optional parameters for NewEnvironmentCredential.
+type EnvironmentCredentialOptions struct {
+	// AuthorityHost is the Azure Active Directory authority host. Defaults to the
+	// value of AZURE_AUTHORITY_HOST or, if that isn't set, DefaultAuthorityHost.
+	AuthorityHost string
+
+	// HTTPClient is the client used to send token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+}
+
+// EnvironmentCredential authenticates a service principal configured with environment variables.
+// AZURE_TENANT_ID and AZURE_CLIENT_ID are required. If AZURE_CLIENT_SECRET is set a ClientSecretCredential
+// is used, otherwise a ClientCertificateCredential is created from the file at AZURE_CLIENT_CERTIFICATE_PATH,
+// decrypted with AZURE_CLIENT_CERTIFICATE_PASSWORD when set.
+type EnvironmentCredential struct {
+	cred   TokenProvider
+	bearer *bearerTokenCredential
+}
+
+// NewEnvironmentCredential creates an EnvironmentCredential. It returns a *CredentialUnavailableError if
+// the environment variables aren't set. Pass nil for options to accept the default values.
+func NewEnvironmentCredential(options *EnvironmentCredentialOptions) (*EnvironmentCredential, error) {
+	if options == nil {
+		options = &EnvironmentCredentialOptions{}
+	}
+	authorityHost := options.AuthorityHost
+	if authorityHost == "" {
+		authorityHost = os.Getenv("AZURE_AUTHORITY_HOST")
+	}
+	tenantID, clientID := os.Getenv("AZURE_TENANT_ID"), os.Getenv("AZURE_CLIENT_ID")
+	if tenantID == "" || clientID == "" {
+		return nil, &CredentialUnavailableError{
+			CredentialType: "EnvironmentCredential",
+			Message:        "AZURE_TENANT_ID and AZURE_CLIENT_ID must be set",
+		}
+	}
+	if secret := os.Getenv("AZURE_CLIENT_SECRET"); secret != "" {
+		cred, err := NewClientSecretCredential(tenantID, clientID, secret, &ClientSecretCredentialOptions{
+			AuthorityHost: authorityHost,
+			HTTPClient:    options.HTTPClient,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return newEnvironmentCredential(cred, options.Scopes), nil
+	}
+	if certPath := os.Getenv("AZURE_CLIENT_CERTIFICATE_PATH"); certPath != "" {
+		cred, err := NewClientCertificateCredentialFromFile(tenantID, clientID, certPath, os.Getenv("AZURE_CLIENT_CERTIFICATE_PASSWORD"), &ClientCertificateCredentialOptions{
+			AuthorityHost: authorityHost,
+			HTTPClient:    options.HTTPClient,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return newEnvironmentCredential(cred, options.Scopes), nil
+	}
+	return nil, &CredentialUnavailableError{
+		CredentialType: "EnvironmentCredential",
+		Message:        "AZURE_CLIENT_SECRET or AZURE_CLIENT_CERTIFICATE_PATH must be set",
+	}
+}
+
+// newEnvironmentCredential creates an EnvironmentCredential that gets tokens from cred.
+func newEnvironmentCredential(cred TokenProvider, scopes []string) *EnvironmentCredential {
+	c := &EnvironmentCredential{cred: cred}
+	c.bearer = newBearerTokenCredential(c, scopes)
+	return c
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*EnvironmentCredential) credentialMarker() {}
+
+// GetToken requests a new token for the requested scopes from the configured credential.
+func (c *EnvironmentCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	return c.cred.GetToken(ctx, opts)
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *EnvironmentCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
@@ -0,0 +1,287 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+const (
+	// DefaultIMDSEndpoint is the token endpoint of the Azure Instance Metadata Service.
+	DefaultIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+	imdsAPIVersion       = "2018-02-01"
+	appServiceAPIVersion = "2019-08-01"
+
+	// environment variables set by App Service and Azure Functions when a managed identity is enabled
+	identityEndpointEnvVar = "IDENTITY_ENDPOINT"
+	identityHeaderEnvVar   = "IDENTITY_HEADER"
+
+	defaultIMDSMaxRetries = 5
+	defaultIMDSRetryDelay = time.Second
+
+	// defaultIMDSProbeTimeout is the probe timeout used by NewDefaultCredential.
+	defaultIMDSProbeTimeout = time.Second
+)
+
+// ManagedIdentityCredentialOptions contains optional parameters for NewManagedIdentityCredential.
+type ManagedIdentityCredentialOptions struct {
+	// ClientID is the client ID of a user-assigned identity. Leave ClientID and ResourceID empty
+	// to use the system-assigned identity.
+	ClientID string
+
+	// ResourceID is the Azure resource ID of a user-assigned identity. It can't be combined with ClientID.
+	ResourceID string
+
+	// IMDSEndpoint is the token endpoint of the Instance Metadata Service. Defaults to DefaultIMDSEndpoint.
+	// The App Service endpoint is read from the IDENTITY_ENDPOINT and IDENTITY_HEADER environment variables.
+	IMDSEndpoint string
+
+	// HTTPClient is the client used to send token requests. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Scopes are the scopes requested for the tokens added to pipeline requests. Defaults to DefaultScope.
+	Scopes []string
+
+	// MaxRetries is the maximum number of times a failed IMDS request is retried. Defaults to 5.
+	MaxRetries int
+
+	// RetryDelay is the delay before the first IMDS retry, it doubles for each subsequent retry. Defaults to one second.
+	RetryDelay time.Duration
+
+	// ProbeTimeout limits how long the first IMDS request may take. If IMDS doesn't respond in time, e.g.
+	// because the code isn't running on Azure, the credential is unavailable and subsequent token requests
+	// fail immediately. Once IMDS has responded requests aren't limited. Defaults to no limit.
+	ProbeTimeout time.Duration
+}
+
+// ManagedIdentityCredential authenticates with the managed identity of the Azure host it runs on.
+// The App Service/Azure Functions endpoint is used when IDENTITY_ENDPOINT and IDENTITY_HEADER are
+// set, otherwise tokens are requested from the Instance Metadata Service (IMDS). Failed IMDS requests
+// are retried with exponential backoff as documented for the service.
+type ManagedIdentityCredential struct {
+	clientID       string
+	resourceID     string
+	endpoint       string
+	identityHeader string
+	httpClient     *http.Client
+	maxRetries     int
+	retryDelay     time.Duration
+	probeTimeout   time.Duration
+	bearer         *bearerTokenCredential
+
+	// lock protects probed, set once IMDS has responded, and unavailable, set if the probe failed
+	lock        sync.Mutex
+	probed      bool
+	unavailable error
+}
+
+// NewManagedIdentityCredential creates a ManagedIdentityCredential. Pass nil for options to use the
+// system-assigned identity and accept the default values.
+func NewManagedIdentityCredential(options *ManagedIdentityCredentialOptions) (*ManagedIdentityCredential, error) {
+	if options == nil {
+		options = &ManagedIdentityCredentialOptions{}
+	}
+	if options.ClientID != "" && options.ResourceID != "" {
+		return nil, fmt.Errorf("ClientID and ResourceID can't both be specified")
+	}
+	c := &ManagedIdentityCredential{
+		clientID:     options.ClientID,
+		resourceID:   options.ResourceID,
+		endpoint:     options.IMDSEndpoint,
+		httpClient:   options.HTTPClient,
+		maxRetries:   options.MaxRetries,
+		retryDelay:   options.RetryDelay,
+		probeTimeout: options.ProbeTimeout,
+	}
+	if ep, h := os.Getenv(identityEndpointEnvVar), os.Getenv(identityHeaderEnvVar); ep != "" && h != "" {
+		c.endpoint = ep
+		c.identityHeader = h
+	} else if c.endpoint == "" {
+		c.endpoint = DefaultIMDSEndpoint
+	}
+	if _, err := url.Parse(c.endpoint); err != nil {
+		return nil, fmt.Errorf("invalid managed identity endpoint %q: %v", c.endpoint, err)
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.maxRetries <= 0 {
+		c.maxRetries = defaultIMDSMaxRetries
+	}
+	if c.retryDelay <= 0 {
+		c.retryDelay = defaultIMDSRetryDelay
+	}
+	c.bearer = newBearerTokenCredential(c, options.Scopes)
+	return c, nil
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*ManagedIdentityCredential) credentialMarker() {}
+
+// GetToken requests a new token for the requested scope from the managed identity endpoint.
+// Managed identity endpoints only support requesting a token for a single scope.
+func (c *ManagedIdentityCredential) GetToken(ctx context.Context, opts TokenRequestOptions) (AccessToken, error) {
+	if len(opts.Scopes) != 1 {
+		return AccessToken{}, fmt.Errorf("managed identity requires exactly one scope, got %d", len(opts.Scopes))
+	}
+	resource := strings.TrimSuffix(opts.Scopes[0], "/.default")
+	if c.identityHeader != "" {
+		return c.appServiceToken(ctx, resource)
+	}
+	return c.imdsToken(ctx, resource)
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *ManagedIdentityCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.bearer.New(next, po)
+}
+
+// appServiceToken requests a token for resource from the App Service managed identity endpoint.
+func (c *ManagedIdentityCredential) appServiceToken(ctx context.Context, resource string) (AccessToken, error) {
+	req, err := c.newRequest(ctx, appServiceAPIVersion, resource, "mi_res_id")
+	if err != nil {
+		return AccessToken{}, err
+	}
+	req.Header.Set("X-IDENTITY-HEADER", c.identityHeader)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return AccessToken{}, err
+	}
+	return parseTokenResponse(resp)
+}
+
+// imdsToken requests a token for resource from IMDS, retrying responses the service documents as transient.
+func (c *ManagedIdentityCredential) imdsToken(ctx context.Context, resource string) (AccessToken, error) {
+	delay := c.retryDelay
+	for try := 0; ; try++ {
+		resp, err := c.sendIMDS(ctx, resource)
+		if err != nil {
+			return AccessToken{}, err
+		}
+		if resp.StatusCode == http.StatusBadRequest {
+			// IMDS is reachable but the host has no managed identity, or not the requested one
+			resp.Body.Close()
+			return AccessToken{}, &CredentialUnavailableError{
+				CredentialType: "ManagedIdentityCredential",
+				Message:        fmt.Sprintf("the requested identity isn't assigned to this host (%s)", resp.Status),
+			}
+		}
+		if try == c.maxRetries || !retryIMDS(resp.StatusCode) {
+			return parseTokenResponse(resp)
+		}
+		wait := delay
+		if ra, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && time.Duration(ra)*time.Second > wait {
+			wait = time.Duration(ra) * time.Second
+		}
+		resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return AccessToken{}, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// sendIMDS sends a token request for resource to IMDS. Until IMDS has responded the request is limited
+// to the probe timeout, if there is one, and the credential becomes unavailable if it isn't met.
+func (c *ManagedIdentityCredential) sendIMDS(ctx context.Context, resource string) (*http.Response, error) {
+	c.lock.Lock()
+	probe, unavailable := c.probeTimeout > 0 && !c.probed, c.unavailable
+	c.lock.Unlock()
+	if unavailable != nil {
+		return nil, unavailable
+	}
+	reqCtx := ctx
+	if probe {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, c.probeTimeout)
+		defer cancel()
+	}
+	req, err := c.newRequest(reqCtx, imdsAPIVersion, resource, "msi_res_id")
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		unavailable := &CredentialUnavailableError{
+			CredentialType: "ManagedIdentityCredential",
+			Message:        fmt.Sprintf("failed to reach %s: %v", c.endpoint, err),
+		}
+		if probe {
+			c.lock.Lock()
+			c.unavailable = unavailable
+			c.lock.Unlock()
+		}
+		return nil, unavailable
+	}
+	c.lock.Lock()
+	c.probed = true
+	c.lock.Unlock()
+	if probe {
+		// read the body before the probe's context is cancelled
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	}
+	return resp, nil
+}
+
+// retryIMDS returns true for the IMDS status codes that should be retried.
+func retryIMDS(statusCode int) bool {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// newRequest creates a token request for resource. idParam is the query parameter used for a resource ID.
+func (c *ManagedIdentityCredential) newRequest(ctx context.Context, apiVersion string, resource string, idParam string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := req.URL.Query()
+	q.Set("api-version", apiVersion)
+	q.Set("resource", resource)
+	if c.clientID != "" {
+		q.Set("client_id", c.clientID)
+	} else if c.resourceID != "" {
+		q.Set(idParam, c.resourceID)
+	}
+	req.URL.RawQuery = q.Encode()
+	return req.WithContext(ctx), nil
+}
@@ -0,0 +1,255 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestManagedIdentityAppService(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("api-version") != appServiceAPIVersion || q.Get("resource") != "https://management.azure.com" || q.Get("mi_res_id") != "resource" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		if h := r.Header.Get("X-IDENTITY-HEADER"); h != "secret" {
+			t.Errorf("unexpected X-IDENTITY-HEADER %q", h)
+		}
+		if r.Header.Get("Metadata") != "" {
+			t.Error("unexpected Metadata header")
+		}
+		fmt.Fprintf(w, `{"access_token":"token","expires_on":"%d","resource":"https://management.azure.com","token_type":"Bearer"}`, expires)
+	}))
+	defer srv.Close()
+	t.Setenv(identityEndpointEnvVar, srv.URL)
+	t.Setenv(identityHeaderEnvVar, "secret")
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{ResourceID: "resource"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk, err := c.GetToken(context.Background(), testTokenOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tk.Token != "token" || tk.ExpiresOn.Unix() != expires {
+		t.Fatalf("unexpected token %q expiring %v", tk.Token, tk.ExpiresOn)
+	}
+}
+
+func TestManagedIdentityUserAssigned(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	for _, tc := range []struct {
+		name    string
+		options ManagedIdentityCredentialOptions
+		param   string
+		unset   string
+	}{
+		{name: "client ID", options: ManagedIdentityCredentialOptions{ClientID: "id"}, param: "client_id", unset: "msi_res_id"},
+		{name: "resource ID", options: ManagedIdentityCredentialOptions{ResourceID: "id"}, param: "msi_res_id", unset: "client_id"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if q.Get("api-version") != imdsAPIVersion || q.Get(tc.param) != "id" || q.Get(tc.unset) != "" {
+					t.Errorf("unexpected query %q", r.URL.RawQuery)
+				}
+				if r.Header.Get("Metadata") != "true" {
+					t.Error("missing Metadata header")
+				}
+				fmt.Fprint(w, `{"access_token":"token","expires_in":"3600"}`)
+			}))
+			defer srv.Close()
+			tc.options.IMDSEndpoint = srv.URL
+			c, err := NewManagedIdentityCredential(&tc.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tk, err := c.GetToken(context.Background(), testTokenOptions); err != nil || tk.Token != "token" {
+				t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+			}
+		})
+	}
+	if _, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{ClientID: "id", ResourceID: "id"}); err == nil {
+		t.Fatal("expected an error for both ClientID and ResourceID")
+	}
+}
+
+func TestManagedIdentityIMDSRetry(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	for _, status := range []int{http.StatusNotFound, http.StatusGone, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var requests int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&requests, 1) < 3 {
+					w.WriteHeader(status)
+					return
+				}
+				fmt.Fprint(w, `{"access_token":"token","expires_in":"3600"}`)
+			}))
+			defer srv.Close()
+			c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, RetryDelay: time.Millisecond})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tk, err := c.GetToken(context.Background(), testTokenOptions); err != nil || tk.Token != "token" {
+				t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+			}
+			if n := atomic.LoadInt32(&requests); n != 3 {
+				t.Fatalf("expected 3 requests, got %d", n)
+			}
+		})
+	}
+}
+
+func TestManagedIdentityIMDSRetriesExhausted(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, MaxRetries: 2, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetToken(context.Background(), testTokenOptions)
+	var authErr *AuthenticationFailedError
+	if !errors.As(err, &authErr) || authErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected an *AuthenticationFailedError, got %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestManagedIdentityIMDSNotRetried(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.GetToken(context.Background(), testTokenOptions); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+}
+
+func TestManagedIdentityIMDSRetryAfter(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"token","expires_in":"3600"}`)
+	}))
+	defer srv.Close()
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, RetryDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if tk, err := c.GetToken(context.Background(), testTokenOptions); err != nil || tk.Token != "token" {
+		t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+	}
+	if d := time.Since(start); d < time.Second {
+		t.Fatalf("expected the retry to wait for Retry-After, it waited %v", d)
+	}
+}
+
+func TestManagedIdentityProbeTimeout(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, ProbeTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		_, err = c.GetToken(context.Background(), testTokenOptions)
+		var unavailable *CredentialUnavailableError
+		if !errors.As(err, &unavailable) {
+			t.Fatalf("expected a *CredentialUnavailableError, got %v", err)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Fatalf("the request took %v", d)
+		}
+	}
+}
+
+func TestManagedIdentityProbeSucceeds(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			// later requests aren't limited by the probe timeout
+			time.Sleep(200 * time.Millisecond)
+		}
+		fmt.Fprint(w, `{"access_token":"token","expires_in":"3600"}`)
+	}))
+	defer srv.Close()
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL, ProbeTimeout: 100 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if tk, err := c.GetToken(context.Background(), testTokenOptions); err != nil || tk.Token != "token" {
+			t.Fatalf("unexpected token %q, error %v", tk.Token, err)
+		}
+	}
+}
+
+func TestManagedIdentityNoIdentity(t *testing.T) {
+	t.Setenv(identityEndpointEnvVar, "")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_request","error_description":"Identity not found"}`)
+	}))
+	defer srv.Close()
+	c, err := NewManagedIdentityCredential(&ManagedIdentityCredentialOptions{IMDSEndpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.GetToken(context.Background(), testTokenOptions)
+	var unavailable *CredentialUnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("expected a *CredentialUnavailableError, got %v", err)
+	}
+}
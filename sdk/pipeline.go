@@ -15,20 +15,132 @@ package sdk
 // limitations under the License.
 
 import (
+	"context"
+	"net/http"
+	"strings"
+
 	"github.com/Azure/azure-pipeline-go/pipeline"
 	"github.com/jhendrixMSFT/policy-proto-go/policy"
 )
 
+// maxApplicationIDLength is the maximum length of TelemetryOptions.ApplicationID.
+const maxApplicationIDLength = 24
+
+// TelemetryOptions configures the User-Agent header sent with requests.
+type TelemetryOptions struct {
+	// ApplicationID is prepended to the User-Agent header to identify the calling application.
+	// It's truncated to 24 characters and spaces are replaced with '/'.
+	ApplicationID string
+
+	// Disabled omits the SDK's user-agent policy. ApplicationID is still sent if set.
+	Disabled bool
+}
+
+// PipelineOptions contains optional parameters for NewPipeline. The zero value creates
+// the same pipeline as NewDefaultPipeline.
+type PipelineOptions struct {
+	// Retry configures the retry policy.
+	Retry policy.SimpleRetryPolicyConfig
+
+	// Telemetry configures the User-Agent header.
+	Telemetry TelemetryOptions
+
+	// Log configures logging by the pipeline's policies.
+	Log pipeline.LogOptions
+
+	// HTTPSender is the policy factory that sends requests. Defaults to a sender that
+	// uses HTTPClient, or a sender that persists cookies if HTTPClient is nil.
+	HTTPSender pipeline.Factory
+
+	// HTTPClient is the client used to send requests. It's ignored when HTTPSender is set.
+	HTTPClient *http.Client
+
+	// PerCallPolicies are run once per call, before the retry policy.
+	PerCallPolicies []pipeline.Factory
+
+	// PerRetryPolicies are run for each try of a call, after the retry policy and before the credential.
+	PerRetryPolicies []pipeline.Factory
+
+	// DisableRPRegistration omits the policy that registers resource providers
+	// when a request fails because the provider isn't registered.
+	DisableRPRegistration bool
+
+	// Anonymous allows a nil credential, sending requests without authorization as if
+	// NewAnonymousCredential was passed, e.g. for public endpoints and local emulators.
+	Anonymous bool
+}
+
+// NewDefaultPipeline creates a pipeline that authorizes requests with c, e.g. the ChainedCredential
+// returned by NewDefaultCredential. Use NewAnonymousCredential for requests that don't need authorization.
 func NewDefaultPipeline(c Credential) pipeline.Pipeline {
+	return NewPipeline(c, nil)
+}
+
+// NewPipeline creates a pipeline that authorizes requests with c, configured by options.
+// For requests that don't need authorization, use NewAnonymousCredential or pass a nil c with
+// options.Anonymous set. Pass nil for options to accept the default values.
+func NewPipeline(c Credential, options *PipelineOptions) pipeline.Pipeline {
+	if options == nil {
+		options = &PipelineOptions{}
+	}
 	if c == nil {
-		panic("c can't be nil")
+		if !options.Anonymous {
+			panic("c can't be nil, use NewAnonymousCredential or PipelineOptions.Anonymous for requests that don't need authorization")
+		}
+		c = NewAnonymousCredential()
+	}
+	f := []pipeline.Factory{}
+	if !options.Telemetry.Disabled {
+		f = append(f, policy.NewUserAgentPolicyFactory())
+	}
+	if options.Telemetry.ApplicationID != "" {
+		f = append(f, newApplicationIDPolicyFactory(options.Telemetry.ApplicationID))
+	}
+	if !options.DisableRPRegistration {
+		f = append(f, policy.NewResourceProviderRegistrar())
 	}
-	f := []pipeline.Factory{
-		policy.NewUserAgentPolicyFactory(),
-		policy.NewResourceProviderRegistrar(),
-		policy.NewSimpleRetryPolicyFactory(policy.SimpleRetryPolicyConfig{}),
-		c,
-		pipeline.MethodFactoryMarker(),
+	f = append(f, options.PerCallPolicies...)
+	f = append(f, policy.NewSimpleRetryPolicyFactory(options.Retry))
+	f = append(f, options.PerRetryPolicies...)
+	f = append(f, c, pipeline.MethodFactoryMarker())
+	sender := options.HTTPSender
+	if sender == nil {
+		if options.HTTPClient != nil {
+			sender = &httpClientSender{client: options.HTTPClient}
+		} else {
+			sender = policy.NewHTTPSenderWithCookiesFactory()
+		}
 	}
-	return pipeline.NewPipeline(f, pipeline.Options{HTTPSender: policy.NewHTTPSenderWithCookiesFactory()})
+	return pipeline.NewPipeline(f, pipeline.Options{HTTPSender: sender, Log: options.Log})
+}
+
+// newApplicationIDPolicyFactory creates a policy factory that prepends appID to the User-Agent header.
+func newApplicationIDPolicyFactory(appID string) pipeline.Factory {
+	appID = strings.ReplaceAll(appID, " ", "/")
+	if len(appID) > maxApplicationIDLength {
+		appID = appID[:maxApplicationIDLength]
+	}
+	return pipeline.FactoryFunc(func(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.PolicyFunc {
+		return func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+			ua := appID
+			if existing := req.Header.Get("User-Agent"); existing != "" {
+				ua += " " + existing
+			}
+			req.Header.Set("User-Agent", ua)
+			return next.Do(ctx, req)
+		}
+	})
+}
+
+// httpClientSender is the policy factory that sends requests with a user-provided http.Client.
+type httpClientSender struct {
+	client *http.Client
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (s *httpClientSender) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		resp, err := s.client.Do(req.WithContext(ctx))
+		return pipeline.NewHTTPResponse(resp), err
+	})
 }
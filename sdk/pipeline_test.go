@@ -0,0 +1,231 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+func TestAnonymousPipeline(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header["Authorization"]
+	}))
+	defer srv.Close()
+	for name, pl := range map[string]pipeline.Pipeline{
+		"credential": NewDefaultPipeline(NewAnonymousCredential()),
+		"option":     NewPipeline(nil, &PipelineOptions{Anonymous: true}),
+	} {
+		auth = nil
+		if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if auth != nil {
+			t.Fatalf("%s: unexpected Authorization header %q", name, auth)
+		}
+	}
+}
+
+func TestNilCredentialPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic")
+		}
+	}()
+	NewPipeline(nil, nil)
+}
+
+// orderRecorder records the order in which its policies run.
+type orderRecorder struct {
+	lock  sync.Mutex
+	order []string
+}
+
+func (o *orderRecorder) record(name string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.order = append(o.order, name)
+}
+
+func (o *orderRecorder) recorded() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return append([]string(nil), o.order...)
+}
+
+// policy returns a policy factory that records name each time it runs.
+func (o *orderRecorder) policy(name string) pipeline.Factory {
+	return pipeline.FactoryFunc(func(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.PolicyFunc {
+		return func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+			o.record(name)
+			return next.Do(ctx, req)
+		}
+	})
+}
+
+// recordingCredential is a Credential that records when it runs.
+type recordingCredential struct {
+	o *orderRecorder
+}
+
+func (recordingCredential) credentialMarker() {}
+
+func (c recordingCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return c.o.policy("credential").New(next, po)
+}
+
+// twiceFactory sends each request twice, standing in for a retry policy that retries once.
+var twiceFactory = pipeline.FactoryFunc(func(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.PolicyFunc {
+	return func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		resp, err := next.Do(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		resp.Response().Body.Close()
+		return next.Do(ctx, req)
+	}
+})
+
+func TestPipelinePolicyOrder(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	o := &orderRecorder{}
+	p := NewPipeline(recordingCredential{o: o}, &PipelineOptions{
+		PerCallPolicies:  []pipeline.Factory{o.policy("call"), twiceFactory},
+		PerRetryPolicies: []pipeline.Factory{o.policy("retry")},
+	})
+	if _, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	// per-call policies run once, per-retry policies run before the credential for every try
+	expected := []string{"call", "retry", "credential", "retry", "credential"}
+	if order := o.recorded(); !reflect.DeepEqual(order, expected) {
+		t.Fatalf("expected policies to run in order %v, got %v", expected, order)
+	}
+}
+
+func TestPipelineApplicationID(t *testing.T) {
+	var ua string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.Header.Get("User-Agent")
+	}))
+	defer srv.Close()
+	for _, tc := range []struct {
+		name      string
+		telemetry TelemetryOptions
+		expected  string
+	}{
+		{name: "disabled", telemetry: TelemetryOptions{Disabled: true}, expected: "sdk/1.0"},
+		{name: "short", telemetry: TelemetryOptions{ApplicationID: "app", Disabled: true}, expected: "app sdk/1.0"},
+		{name: "truncated", telemetry: TelemetryOptions{ApplicationID: "my application with a long name", Disabled: true}, expected: "my/application/with/a/lo sdk/1.0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewPipeline(NewAnonymousCredential(), &PipelineOptions{Telemetry: tc.telemetry})
+			req := newTestRequest(t, http.MethodGet, srv.URL, "")
+			req.Header.Set("User-Agent", "sdk/1.0")
+			if _, err := p.Do(context.Background(), nil, req); err != nil {
+				t.Fatal(err)
+			}
+			if ua != tc.expected {
+				t.Fatalf("expected User-Agent %q, got %q", tc.expected, ua)
+			}
+		})
+	}
+	// the application ID is prepended to the User-Agent set by the SDK's policy
+	p := NewPipeline(NewAnonymousCredential(), &PipelineOptions{Telemetry: TelemetryOptions{ApplicationID: "app"}})
+	if _, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ua, "app") {
+		t.Fatalf("expected User-Agent to start with the application ID, got %q", ua)
+	}
+}
+
+// countingTransport counts the requests it sends.
+type countingTransport struct {
+	lock     sync.Mutex
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.lock.Lock()
+	c.requests++
+	c.lock.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestPipelineHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+	p := NewPipeline(NewAnonymousCredential(), &PipelineOptions{HTTPClient: client})
+	if _, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if transport.requests != 1 {
+		t.Fatalf("expected the client to send 1 request, got %d", transport.requests)
+	}
+	// HTTPSender takes precedence over HTTPClient
+	sent := 0
+	sender := pipeline.FactoryFunc(func(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.PolicyFunc {
+		return func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+			sent++
+			return pipeline.NewHTTPResponse(&http.Response{StatusCode: http.StatusOK, Body: http.NoBody}), nil
+		}
+	})
+	p = NewPipeline(NewAnonymousCredential(), &PipelineOptions{HTTPClient: client, HTTPSender: sender})
+	if _, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL, "")); err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || transport.requests != 1 {
+		t.Fatalf("expected the sender to send the request, sender %d, client %d", sent, transport.requests)
+	}
+}
+
+func TestPipelineDisableRPRegistration(t *testing.T) {
+	var lock sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		lock.Unlock()
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error":{"code":"MissingSubscriptionRegistration","message":"The subscription is not registered to use namespace 'Microsoft.Cache'."}}`)
+	}))
+	defer srv.Close()
+	p := NewPipeline(NewAnonymousCredential(), &PipelineOptions{DisableRPRegistration: true})
+	resp, err := p.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL+"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cache/redis/cache", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Response().StatusCode != http.StatusConflict {
+		t.Fatalf("unexpected status %d", resp.Response().StatusCode)
+	}
+	// the provider isn't registered
+	expected := []string{"GET /subscriptions/sub/resourceGroups/rg/providers/Microsoft.Cache/redis/cache"}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("expected requests %v, got %v", expected, requests)
+	}
+}
@@ -0,0 +1,74 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// NewSASCredential creates a SASCredential from sas, a shared access signature query string
+// such as "sv=2019-02-02&ss=b&sig=...". A leading '?' is ignored.
+func NewSASCredential(sas string) (*SASCredential, error) {
+	c := &SASCredential{}
+	if err := c.SetSAS(sas); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SASCredential authorizes requests by adding a shared access signature to their query string.
+type SASCredential struct {
+	sas atomic.Value // url.Values
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*SASCredential) credentialMarker() {}
+
+// SAS returns the current shared access signature.
+func (c *SASCredential) SAS() string {
+	return c.sas.Load().(url.Values).Encode()
+}
+
+// SetSAS replaces the shared access signature added to requests, e.g. before the current one expires.
+func (c *SASCredential) SetSAS(sas string) error {
+	values, err := url.ParseQuery(strings.TrimPrefix(sas, "?"))
+	if err != nil {
+		return fmt.Errorf("sas isn't a valid query string: %v", err)
+	}
+	if values.Get("sig") == "" {
+		return fmt.Errorf("sas doesn't contain a signature")
+	}
+	c.sas.Store(values)
+	return nil
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *SASCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		// the parameters are replaced rather than added so retries get the current signature
+		q := req.URL.Query()
+		for k, v := range c.sas.Load().(url.Values) {
+			q[k] = v
+		}
+		req.URL.RawQuery = q.Encode()
+		return next.Do(ctx, req)
+	})
+}
@@ -0,0 +1,190 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// NewSharedKeyCredential creates a SharedKeyCredential for the storage account accountName
+// using accountKey, the base64-encoded account access key.
+func NewSharedKeyCredential(accountName string, accountKey string) (*SharedKeyCredential, error) {
+	if accountName == "" {
+		return nil, fmt.Errorf("accountName can't be empty")
+	}
+	c := &SharedKeyCredential{accountName: accountName}
+	if err := c.SetAccountKey(accountKey); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SharedKeyCredential authorizes requests with an Azure Storage account name and key. Each request
+// is signed with an HMAC-SHA256 of its canonicalized headers and resource, see
+// https://docs.microsoft.com/rest/api/storageservices/authorize-with-shared-key.
+type SharedKeyCredential struct {
+	accountName string
+	accountKey  atomic.Value // []byte
+}
+
+// credentialMarker is a package-internal method that exists just to satisfy the Credential interface.
+func (*SharedKeyCredential) credentialMarker() {}
+
+// AccountName returns the storage account's name.
+func (c *SharedKeyCredential) AccountName() string {
+	return c.accountName
+}
+
+// SetAccountKey replaces the account key used to sign requests, e.g. after the key is rotated.
+func (c *SharedKeyCredential) SetAccountKey(accountKey string) error {
+	key, err := base64.StdEncoding.DecodeString(accountKey)
+	if err != nil {
+		return fmt.Errorf("accountKey isn't valid base64: %v", err)
+	}
+	c.accountKey.Store(key)
+	return nil
+}
+
+// ComputeHMACSHA256 returns the base64-encoded HMAC-SHA256 of message using the account key.
+func (c *SharedKeyCredential) ComputeHMACSHA256(message string) string {
+	h := hmac.New(sha256.New, c.accountKey.Load().([]byte))
+	h.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// New satisfies pipeline.Factory's New method creating a pipeline policy object.
+func (c *SharedKeyCredential) New(next pipeline.Policy, po *pipeline.PolicyOptions) pipeline.Policy {
+	return pipeline.PolicyFunc(func(ctx context.Context, req pipeline.Request) (pipeline.Response, error) {
+		// the date is set on every try so that retried requests aren't rejected as stale
+		req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+		stringToSign, err := c.buildStringToSign(req)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", c.accountName, c.ComputeHMACSHA256(stringToSign)))
+		resp, err := next.Do(ctx, req)
+		if raw := httpResponse(resp, err); raw != nil && raw.StatusCode == http.StatusForbidden {
+			// the service rejected the signature, log what was signed to help diagnose why
+			po.Log(pipeline.LogError, "HTTP Forbidden status, string-to-sign:\n"+stringToSign)
+		}
+		return resp, err
+	})
+}
+
+// buildStringToSign returns the string that's signed to authorize req.
+func (c *SharedKeyCredential) buildStringToSign(req pipeline.Request) (string, error) {
+	contentLength := req.Header.Get("Content-Length")
+	if contentLength == "" && req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	if contentLength == "0" {
+		contentLength = ""
+	}
+	resource, err := c.buildCanonicalizedResource(req.URL)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date is empty as x-ms-date is always set
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		buildCanonicalizedHeader(req.Header),
+		resource,
+	}, "\n"), nil
+}
+
+// buildCanonicalizedHeader returns the x-ms- headers sorted by name, one name:value pair per line.
+func buildCanonicalizedHeader(headers http.Header) string {
+	cm := map[string][]string{}
+	for k, v := range headers {
+		name := strings.ToLower(strings.TrimSpace(k))
+		if strings.HasPrefix(name, "x-ms-") {
+			cm[name] = append(cm[name], v...)
+		}
+	}
+	if len(cm) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(cm))
+	for k := range cm {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ch := bytes.Buffer{}
+	for i, k := range keys {
+		if i > 0 {
+			ch.WriteRune('\n')
+		}
+		ch.WriteString(k)
+		ch.WriteRune(':')
+		ch.WriteString(strings.Join(cm[k], ","))
+	}
+	return ch.String()
+}
+
+// buildCanonicalizedResource returns the account and path of u followed by its
+// query parameters sorted by name, one name:values pair per line.
+func (c *SharedKeyCredential) buildCanonicalizedResource(u *url.URL) (string, error) {
+	cr := bytes.NewBufferString("/")
+	cr.WriteString(c.accountName)
+	if len(u.Path) > 0 {
+		cr.WriteString(u.EscapedPath())
+	} else {
+		cr.WriteString("/")
+	}
+	params, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse query parameters: %v", err)
+	}
+	lowered := map[string][]string{}
+	for k, v := range params {
+		name := strings.ToLower(k)
+		lowered[name] = append(lowered[name], v...)
+	}
+	names := make([]string, 0, len(lowered))
+	for k := range lowered {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := lowered[name]
+		sort.Strings(values)
+		cr.WriteString("\n" + name + ":" + strings.Join(values, ","))
+	}
+	return cr.String(), nil
+}
@@ -0,0 +1,198 @@
+package sdk
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+)
+
+// the well-known key of the storage emulator's devstoreaccount1 account
+const testAccountKey = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+
+func newTestSharedKeyCredential(t *testing.T) *SharedKeyCredential {
+	c, err := NewSharedKeyCredential("myaccount", testAccountKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// The expected values below are the examples in
+// https://docs.microsoft.com/rest/api/storageservices/authorize-with-shared-key.
+// The signatures were computed independently with Python's hmac module.
+
+func TestSharedKeyCanonicalizedResource(t *testing.T) {
+	c := newTestSharedKeyCredential(t)
+	for rawurl, want := range map[string]string{
+		"https://myaccount.blob.core.windows.net/mycontainer?restype=container&comp=metadata":                                                         "/myaccount/mycontainer\ncomp:metadata\nrestype:container",
+		"https://myaccount.blob.core.windows.net/mycontainer?restype=container&comp=list&include=snapshots&include=metadata&include=uncommittedblobs": "/myaccount/mycontainer\ncomp:list\ninclude:metadata,snapshots,uncommittedblobs\nrestype:container",
+		// the secondary location is signed with the account name
+		"https://myaccount-secondary.blob.core.windows.net/mycontainer/myblob": "/myaccount/mycontainer/myblob",
+		"https://myaccount.blob.core.windows.net/?comp=list":                   "/myaccount/\ncomp:list",
+		"https://myaccount.blob.core.windows.net":                              "/myaccount/",
+	} {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := c.buildCanonicalizedResource(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("canonicalized resource of %s:\n%q\nwant:\n%q", rawurl, got, want)
+		}
+	}
+}
+
+func TestSharedKeyCanonicalizedHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-ms-version", "2015-02-21")
+	h.Set("X-Ms-Date", "Fri, 26 Jun 2015 23:39:12 GMT")
+	h.Set("x-ms-meta-a", "1")
+	h.Add("x-ms-meta-a", "2")
+	h.Set("Content-Type", "text/plain")
+	want := "x-ms-date:Fri, 26 Jun 2015 23:39:12 GMT\nx-ms-meta-a:1,2\nx-ms-version:2015-02-21"
+	if got := buildCanonicalizedHeader(h); got != want {
+		t.Errorf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestSharedKeyStringToSign(t *testing.T) {
+	c := newTestSharedKeyCredential(t)
+	for _, tc := range []struct {
+		method, url, body string
+		headers           map[string]string
+		stringToSign      string
+		signature         string
+	}{
+		{
+			method: http.MethodGet,
+			url:    "https://myaccount.blob.core.windows.net/mycontainer?restype=container&comp=metadata&timeout=20",
+			headers: map[string]string{
+				"x-ms-date":    "Fri, 26 Jun 2015 23:39:12 GMT",
+				"x-ms-version": "2015-02-21",
+			},
+			stringToSign: "GET\n\n\n\n\n\n\n\n\n\n\n\n" +
+				"x-ms-date:Fri, 26 Jun 2015 23:39:12 GMT\nx-ms-version:2015-02-21\n" +
+				"/myaccount/mycontainer\ncomp:metadata\nrestype:container\ntimeout:20",
+			signature: "1u9lui2jDxj0+fpbHjQ5m5NnastJRSYM+PSmfi8TXx4=",
+		},
+		{
+			method: http.MethodPut,
+			url:    "https://myaccount.blob.core.windows.net/mycontainer/myblob",
+			body:   "hello world",
+			headers: map[string]string{
+				"Content-Type":   "text/plain; charset=UTF-8",
+				"x-ms-blob-type": "BlockBlob",
+				"x-ms-date":      "Fri, 26 Jun 2015 23:39:12 GMT",
+				"x-ms-version":   "2015-02-21",
+			},
+			stringToSign: "PUT\n\n\n11\n\ntext/plain; charset=UTF-8\n\n\n\n\n\n\n" +
+				"x-ms-blob-type:BlockBlob\nx-ms-date:Fri, 26 Jun 2015 23:39:12 GMT\nx-ms-version:2015-02-21\n" +
+				"/myaccount/mycontainer/myblob",
+			signature: "G2BrBWmkNKQIJGHlqL+fPfZgtmSaPgml2zHqgIK68rY=",
+		},
+	} {
+		req := newTestRequest(t, tc.method, tc.url, tc.body)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		got, err := c.buildStringToSign(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.stringToSign {
+			t.Errorf("string-to-sign for %s %s:\n%q\nwant:\n%q", tc.method, tc.url, got, tc.stringToSign)
+		}
+		if sig := c.ComputeHMACSHA256(got); sig != tc.signature {
+			t.Errorf("signature for %s %s is %s, want %s", tc.method, tc.url, sig, tc.signature)
+		}
+	}
+}
+
+func TestSharedKeyPolicy(t *testing.T) {
+	c := newTestSharedKeyCredential(t)
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+	}))
+	defer srv.Close()
+	pl := pipeline.NewPipeline([]pipeline.Factory{c, pipeline.MethodFactoryMarker()}, pipeline.Options{})
+	req := newTestRequest(t, http.MethodGet, srv.URL+"/mycontainer?comp=list", "")
+	// a stale date, e.g. from a previous try, is replaced
+	req.Header.Set("x-ms-date", "Fri, 26 Jun 2015 23:39:12 GMT")
+	req.Header.Set("x-ms-version", "2015-02-21")
+	if _, err := pl.Do(context.Background(), nil, req); err != nil {
+		t.Fatal(err)
+	}
+	date, err := time.Parse(http.TimeFormat, got.Header.Get("x-ms-date"))
+	if err != nil || time.Since(date) > time.Minute {
+		t.Fatalf("unexpected x-ms-date %q", got.Header.Get("x-ms-date"))
+	}
+	stringToSign := "GET\n\n\n\n\n\n\n\n\n\n\n\n" +
+		"x-ms-date:" + got.Header.Get("x-ms-date") + "\nx-ms-version:2015-02-21\n" +
+		"/myaccount/mycontainer\ncomp:list"
+	if want := "SharedKey myaccount:" + c.ComputeHMACSHA256(stringToSign); got.Header.Get("Authorization") != want {
+		t.Fatalf("Authorization is %q, want %q", got.Header.Get("Authorization"), want)
+	}
+}
+
+func TestSharedKeyInvalidKey(t *testing.T) {
+	if _, err := NewSharedKeyCredential("myaccount", "not base64!"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := NewSharedKeyCredential("", testAccountKey); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSASCredential(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+	}))
+	defer srv.Close()
+	c, err := NewSASCredential("?sv=2019-02-02&sig=abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := pipeline.NewPipeline([]pipeline.Factory{c, pipeline.MethodFactoryMarker()}, pipeline.Options{})
+	if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL+"/c?comp=list", "")); err != nil {
+		t.Fatal(err)
+	}
+	if query != "comp=list&sig=abc&sv=2019-02-02" {
+		t.Fatalf("unexpected query %q", query)
+	}
+	if err := c.SetSAS("sv=2019-02-02&sig=def"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pl.Do(context.Background(), nil, newTestRequest(t, http.MethodGet, srv.URL+"/c", "")); err != nil {
+		t.Fatal(err)
+	}
+	if query != "sig=def&sv=2019-02-02" || c.SAS() != query {
+		t.Fatalf("unexpected query %q", query)
+	}
+	if _, err := NewSASCredential("sv=2019-02-02"); err == nil {
+		t.Fatal("expected an error for a SAS without a signature")
+	}
+}
@@ -38,10 +38,22 @@ type Client struct {
 	s string
 }
 
+func (c Client) FirewallRules() IFirewallRules {
+	return firewallRulesClient{&c}
+}
+
+func (c Client) LinkedServers() ILinkedServers {
+	return linkedServersClient{&c}
+}
+
 func (c Client) Operations() IOperations {
 	return operationsClient{&c}
 }
 
+func (c Client) PatchSchedules() IPatchSchedules {
+	return patchSchedulesClient{&c}
+}
+
 func (c Client) Redis() IRedis {
 	return client{&c}
 }
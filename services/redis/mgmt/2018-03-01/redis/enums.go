@@ -61,6 +61,21 @@ func PossibleKeyTypeValues() []KeyType {
 	return []KeyType{Primary, Secondary}
 }
 
+// NameUnavailableReason enumerates the values for name unavailable reason.
+type NameUnavailableReason string
+
+const (
+	// AlreadyExists ...
+	AlreadyExists NameUnavailableReason = "AlreadyExists"
+	// Invalid ...
+	Invalid NameUnavailableReason = "Invalid"
+)
+
+// PossibleNameUnavailableReasonValues returns an array of possible values for the NameUnavailableReason const type.
+func PossibleNameUnavailableReasonValues() []NameUnavailableReason {
+	return []NameUnavailableReason{AlreadyExists, Invalid}
+}
+
 // ProvisioningState enumerates the values for provisioning state.
 type ProvisioningState string
 
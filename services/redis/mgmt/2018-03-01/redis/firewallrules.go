@@ -0,0 +1,275 @@
+package redis
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/validation"
+)
+
+type IFirewallRules interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, cacheName string, ruleName string, parameters FirewallRuleCreateParameters) (*FirewallRule, error)
+	Delete(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (*FirewallRulesDeleteResponse, error)
+	Get(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (*FirewallRule, error)
+	List(resourceGroupName string, cacheName string) *FirewallRuleListResultPager
+}
+
+// FirewallRulesClient is the REST API for Azure Redis Cache Service.
+type firewallRulesClient struct {
+	*Client
+}
+
+// CreateOrUpdate create or update a redis cache firewall rule
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// cacheName - the name of the Redis cache.
+// ruleName - the name of the firewall rule.
+// parameters - parameters supplied to the create or update redis firewall rule operation.
+func (c firewallRulesClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, cacheName string, ruleName string, parameters FirewallRuleCreateParameters) (*FirewallRule, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.FirewallRuleProperties", Name: validation.Null, Rule: true,
+				Chain: []validation.Constraint{{Target: "parameters.FirewallRuleProperties.StartIP", Name: validation.Null, Rule: true,
+					Chain: []validation.Constraint{{Target: "parameters.FirewallRuleProperties.StartIP", Name: validation.Pattern, Rule: `^\d+\.\d+\.\d+\.\d+$`, Chain: nil}}},
+					{Target: "parameters.FirewallRuleProperties.EndIP", Name: validation.Null, Rule: true,
+						Chain: []validation.Constraint{{Target: "parameters.FirewallRuleProperties.EndIP", Name: validation.Pattern, Rule: `^\d+\.\d+\.\d+\.\d+$`, Chain: nil}}},
+				}}}}}); err != nil {
+		return nil, validation.NewError("redis.FirewallRulesClient", "CreateOrUpdate", "%s", err.Error())
+	}
+	if err := validateFirewallRuleRange(*parameters.StartIP, *parameters.EndIP); err != nil {
+		return nil, validation.NewError("redis.FirewallRulesClient", "CreateOrUpdate", "%s", err.Error())
+	}
+	req, err := c.createOrUpdatePreparer(ctx, resourceGroupName, cacheName, ruleName, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.createOrUpdateResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*FirewallRule), err
+}
+
+// CreateOrUpdatePreparer prepares the CreateOrUpdate request.
+func (c firewallRulesClient) createOrUpdatePreparer(ctx context.Context, resourceGroupName string, cacheName string, ruleName string, parameters FirewallRuleCreateParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{cacheName}/firewallRules/{ruleName}", map[string]string{
+		"cacheName":         cacheName,
+		"resourceGroupName": resourceGroupName,
+		"ruleName":          ruleName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPut, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// CreateOrUpdateResponder handles the response to the CreateOrUpdate request. The method always
+// closes the http.Response Body.
+func (c firewallRulesClient) createOrUpdateResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated)
+	if resp == nil {
+		return nil, err
+	}
+	result := &FirewallRule{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// Delete deletes a single firewall rule in a specified redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// cacheName - the name of the Redis cache.
+// ruleName - the name of the firewall rule.
+func (c firewallRulesClient) Delete(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (*FirewallRulesDeleteResponse, error) {
+	req, err := c.deletePreparer(ctx, resourceGroupName, cacheName, ruleName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.deleteResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*FirewallRulesDeleteResponse), err
+}
+
+// DeletePreparer prepares the Delete request.
+func (c firewallRulesClient) deletePreparer(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{cacheName}/firewallRules/{ruleName}", map[string]string{
+		"cacheName":         cacheName,
+		"resourceGroupName": resourceGroupName,
+		"ruleName":          ruleName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// DeleteResponder handles the response to the Delete request. The method always
+// closes the http.Response Body.
+func (c firewallRulesClient) deleteResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &FirewallRulesDeleteResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, resp.Response().Body.Close()
+}
+
+// Get gets a single firewall rule in a specified redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// cacheName - the name of the Redis cache.
+// ruleName - the name of the firewall rule.
+func (c firewallRulesClient) Get(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (*FirewallRule, error) {
+	req, err := c.getPreparer(ctx, resourceGroupName, cacheName, ruleName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.getResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*FirewallRule), err
+}
+
+// GetPreparer prepares the Get request.
+func (c firewallRulesClient) getPreparer(ctx context.Context, resourceGroupName string, cacheName string, ruleName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{cacheName}/firewallRules/{ruleName}", map[string]string{
+		"cacheName":         cacheName,
+		"resourceGroupName": resourceGroupName,
+		"ruleName":          ruleName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// GetResponder handles the response to the Get request. The method always
+// closes the http.Response Body.
+func (c firewallRulesClient) getResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &FirewallRule{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// List gets all firewall rules in the specified redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// cacheName - the name of the Redis cache.
+func (c firewallRulesClient) List(resourceGroupName string, cacheName string) *FirewallRuleListResultPager {
+	return &FirewallRuleListResultPager{runtime.NewPager(c.p, func(ctx context.Context) (pipeline.Request, error) {
+		return c.listPreparer(ctx, resourceGroupName, cacheName)
+	}, c.listResponder, func(frlr *FirewallRuleListResult) *string {
+		return frlr.NextLink
+	})}
+}
+
+// ListPreparer prepares the List request.
+func (c firewallRulesClient) listPreparer(ctx context.Context, resourceGroupName string, cacheName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{cacheName}/firewallRules", map[string]string{
+		"cacheName":         cacheName,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ListResponder handles the response to the List request. The method always
+// closes the http.Response Body.
+func (c firewallRulesClient) listResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &FirewallRuleListResult{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// validateFirewallRuleRange returns an error if startIP or endIP isn't an IPv4 address or startIP is greater than endIP.
+func validateFirewallRuleRange(startIP string, endIP string) error {
+	start := net.ParseIP(startIP).To4()
+	if start == nil {
+		return fmt.Errorf("parameters.FirewallRuleProperties.StartIP %q isn't a valid IPv4 address", startIP)
+	}
+	end := net.ParseIP(endIP).To4()
+	if end == nil {
+		return fmt.Errorf("parameters.FirewallRuleProperties.EndIP %q isn't a valid IPv4 address", endIP)
+	}
+	if bytes.Compare(start, end) > 0 {
+		return fmt.Errorf("parameters.FirewallRuleProperties.StartIP %s is greater than EndIP %s", startIP, endIP)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package redis
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import "testing"
+
+func TestValidateFirewallRuleRange(t *testing.T) {
+	for _, tc := range []struct {
+		start, end string
+		valid      bool
+	}{
+		{"10.0.0.1", "10.0.0.1", true},
+		{"10.0.0.1", "10.0.1.0", true},
+		{"0.0.0.0", "255.255.255.255", true},
+		{"999.0.0.1", "999.0.0.2", false},
+		{"10.0.0.1", "10.0.0.256", false},
+		{"10.0.1.0", "10.0.0.255", false},
+		{"::1", "::2", false},
+		{"10.0.0.1", "", false},
+	} {
+		if err := validateFirewallRuleRange(tc.start, tc.end); (err == nil) != tc.valid {
+			t.Errorf("validateFirewallRuleRange(%q, %q) returned %v", tc.start, tc.end, err)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package redis
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/validation"
+)
+
+type ILinkedServers interface {
+	Create(ctx context.Context, resourceGroupName string, name string, linkedServerName string, parameters LinkedServerCreateParameters) (*LinkedServerCreatePoller, error)
+	Delete(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (*LinkedServersDeleteResponse, error)
+	Get(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (*LinkedServerWithProperties, error)
+	List(resourceGroupName string, name string) *LinkedServerWithPropertiesListPager
+}
+
+// LinkedServersClient is the REST API for Azure Redis Cache Service.
+type linkedServersClient struct {
+	*Client
+}
+
+// Create adds a linked server to the Redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// linkedServerName - the name of the linked server that is being added to the Redis cache.
+// parameters - parameters supplied to the Create Linked server operation.
+func (c linkedServersClient) Create(ctx context.Context, resourceGroupName string, name string, linkedServerName string, parameters LinkedServerCreateParameters) (*LinkedServerCreatePoller, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.LinkedServerCreateProperties", Name: validation.Null, Rule: true,
+				Chain: []validation.Constraint{{Target: "parameters.LinkedServerCreateProperties.LinkedRedisCacheID", Name: validation.Null, Rule: true, Chain: nil},
+					{Target: "parameters.LinkedServerCreateProperties.LinkedRedisCacheLocation", Name: validation.Null, Rule: true, Chain: nil},
+				}}}}}); err != nil {
+		return nil, validation.NewError("redis.LinkedServersClient", "Create", "%s", err.Error())
+	}
+	req, err := c.createPreparer(ctx, resourceGroupName, name, linkedServerName, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[*LinkedServerWithProperties]("redis.LinkedServer.Create", c.p, resp, c.createResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkedServerCreatePoller{poller}, nil
+}
+
+// ResumeLinkedServerCreate creates a LinkedServerCreatePoller from a token obtained from
+// LinkedServerCreatePoller.ResumeToken, allowing an in-flight linked server Create operation
+// to be polled by a different process.
+func (c Client) ResumeLinkedServerCreate(token string) (*LinkedServerCreatePoller, error) {
+	poller, err := runtime.NewPollerFromResumeToken[*LinkedServerWithProperties]("redis.LinkedServer.Create", c.p, token, linkedServersClient{&c}.createResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &LinkedServerCreatePoller{poller}, nil
+}
+
+// CreatePreparer prepares the Create request.
+func (c linkedServersClient) createPreparer(ctx context.Context, resourceGroupName string, name string, linkedServerName string, parameters LinkedServerCreateParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/linkedServers/{linkedServerName}", map[string]string{
+		"linkedServerName":  linkedServerName,
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPut, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// CreateResponder handles the final response to the Create request. The method always
+// closes the http.Response Body.
+func (c linkedServersClient) createResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated)
+	if resp == nil {
+		return nil, err
+	}
+	result := &LinkedServerWithProperties{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// Delete deletes the linked server from a redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the redis cache.
+// linkedServerName - the name of the linked server that is being removed from the Redis cache.
+func (c linkedServersClient) Delete(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (*LinkedServersDeleteResponse, error) {
+	req, err := c.deletePreparer(ctx, resourceGroupName, name, linkedServerName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.deleteResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*LinkedServersDeleteResponse), err
+}
+
+// DeletePreparer prepares the Delete request.
+func (c linkedServersClient) deletePreparer(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/linkedServers/{linkedServerName}", map[string]string{
+		"linkedServerName":  linkedServerName,
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// DeleteResponder handles the response to the Delete request. The method always
+// closes the http.Response Body.
+func (c linkedServersClient) deleteResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &LinkedServersDeleteResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, resp.Response().Body.Close()
+}
+
+// Get gets the detailed information about a linked server of a redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the redis cache.
+// linkedServerName - the name of the linked server.
+func (c linkedServersClient) Get(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (*LinkedServerWithProperties, error) {
+	req, err := c.getPreparer(ctx, resourceGroupName, name, linkedServerName)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.getResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*LinkedServerWithProperties), err
+}
+
+// GetPreparer prepares the Get request.
+func (c linkedServersClient) getPreparer(ctx context.Context, resourceGroupName string, name string, linkedServerName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/linkedServers/{linkedServerName}", map[string]string{
+		"linkedServerName":  linkedServerName,
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// GetResponder handles the response to the Get request. The method always
+// closes the http.Response Body.
+func (c linkedServersClient) getResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &LinkedServerWithProperties{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// List gets the list of linked servers associated with this redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the redis cache.
+func (c linkedServersClient) List(resourceGroupName string, name string) *LinkedServerWithPropertiesListPager {
+	return &LinkedServerWithPropertiesListPager{runtime.NewPager(c.p, func(ctx context.Context) (pipeline.Request, error) {
+		return c.listPreparer(ctx, resourceGroupName, name)
+	}, c.listResponder, func(lswpl *LinkedServerWithPropertiesList) *string {
+		return lswpl.NextLink
+	})}
+}
+
+// ListPreparer prepares the List request.
+func (c linkedServersClient) listPreparer(ctx context.Context, resourceGroupName string, name string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/linkedServers", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ListResponder handles the response to the List request. The method always
+// closes the http.Response Body.
+func (c linkedServersClient) listResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &LinkedServerWithPropertiesList{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
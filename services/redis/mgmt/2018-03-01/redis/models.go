@@ -1,10 +1,13 @@
 package redis
 
 import (
+	"context"
+	"encoding/json"
+	"iter"
 	"net/http"
+	"time"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
 )
 
 // Copyright (c) Microsoft and contributors.  All rights reserved.
@@ -33,6 +36,11 @@ type AccessKeys struct {
 	SecondaryKey *string `json:"secondaryKey,omitempty"`
 }
 
+// Response returns the raw HTTP response object.
+func (ak AccessKeys) Response() *http.Response {
+	return ak.rawResponse
+}
+
 // CheckNameAvailabilityParameters parameters body to pass for resource name availability check.
 type CheckNameAvailabilityParameters struct {
 	// Name - Resource name.
@@ -41,9 +49,15 @@ type CheckNameAvailabilityParameters struct {
 	Type *string `json:"type,omitempty"`
 }
 
-// CheckNameAvailabilityResponse ...
+// CheckNameAvailabilityResponse the result of the CheckNameAvailability Redis operation.
 type CheckNameAvailabilityResponse struct {
 	rawResponse *http.Response
+	// NameAvailable - Indicates whether the name is available for use.
+	NameAvailable *bool `json:"nameAvailable,omitempty"`
+	// Reason - The reason the name is unavailable. Possible values include: 'AlreadyExists', 'Invalid'
+	Reason NameUnavailableReason `json:"reason,omitempty"`
+	// Message - Details about why the name is unavailable.
+	Message *string `json:"message,omitempty"`
 }
 
 // Response returns the raw HTTP response object.
@@ -51,34 +65,12 @@ func (cnar CheckNameAvailabilityResponse) Response() *http.Response {
 	return cnar.rawResponse
 }
 
-// CreateFuture an abstraction for monitoring and retrieving the results of a long-running operation.
-type CreateFuture struct {
-	azure.Future
+// CreatePoller provides polling facilities until the Create operation reaches a terminal state.
+// Result and PollUntilDone return the Redis cache once the operation has succeeded.
+type CreatePoller struct {
+	*runtime.Poller[*ResourceType]
 }
 
-// Result returns the result of the asynchronous operation.
-// If the operation has not completed it will return an error.
-/*func (future *CreateFuture) Result(client Client) (rt ResourceType, err error) {
-	var done bool
-	done, err = future.Done(client)
-	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.CreateFuture", "Result", future.Response(), "Polling failure")
-		return
-	}
-	if !done {
-		err = azure.NewAsyncOpIncompleteError("redis.CreateFuture")
-		return
-	}
-	sender := autorest.DecorateSender(client, autorest.DoRetryForStatusCodes(client.RetryAttempts, client.RetryDuration, autorest.StatusCodesForRetry...))
-	if rt.Response.Response, err = future.GetResult(sender); err == nil && rt.Response.Response.StatusCode != http.StatusNoContent {
-		rt, err = client.CreateResponder(rt.Response.Response)
-		if err != nil {
-			err = autorest.NewErrorWithError(err, "redis.CreateFuture", "Result", rt.Response.Response, "Failure responding to request")
-		}
-	}
-	return
-}*/
-
 // CreateParameters parameters supplied to the Create Redis operation.
 type CreateParameters struct {
 	// CreateProperties - Redis cache properties.
@@ -111,12 +103,282 @@ type CreateProperties struct {
 	MinimumTLSVersion TLSVersion `json:"minimumTlsVersion,omitempty"`
 }
 
+// DeletePoller provides polling facilities until the Delete operation reaches a terminal state.
+type DeletePoller struct {
+	*runtime.Poller[*DeleteResponse]
+}
+
+// DeleteResponse ...
+type DeleteResponse struct {
+	rawResponse *http.Response
+}
+
+// Response returns the raw HTTP response object.
+func (dr DeleteResponse) Response() *http.Response {
+	return dr.rawResponse
+}
+
+// ExportDataPoller provides polling facilities until the ExportData operation reaches a terminal state.
+type ExportDataPoller struct {
+	*runtime.Poller[*ExportDataResponse]
+}
+
+// ExportDataResponse ...
+type ExportDataResponse struct {
+	rawResponse *http.Response
+}
+
+// Response returns the raw HTTP response object.
+func (edr ExportDataResponse) Response() *http.Response {
+	return edr.rawResponse
+}
+
+// ExportRDBParameters parameters for Redis export operation.
+type ExportRDBParameters struct {
+	// Format - File format.
+	Format *string `json:"format,omitempty"`
+	// Prefix - Prefix to use for exported files.
+	Prefix *string `json:"prefix,omitempty"`
+	// Container - Container name to export to.
+	Container *string `json:"container,omitempty"`
+}
+
+// FirewallRule a firewall rule on a redis cache has a name, and describes a contiguous range of IP addresses
+// permitted to connect
+type FirewallRule struct {
+	rawResponse *http.Response
+	// FirewallRuleProperties - redis cache firewall rule properties
+	*FirewallRuleProperties `json:"properties,omitempty"`
+	// ID - Resource ID.
+	ID *string `json:"id,omitempty"`
+	// Name - Resource name.
+	Name *string `json:"name,omitempty"`
+	// Type - Resource type.
+	Type *string `json:"type,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (fr FirewallRule) Response() *http.Response {
+	return fr.rawResponse
+}
+
+// FirewallRuleCreateParameters parameters required for creating a firewall rule on redis cache.
+type FirewallRuleCreateParameters struct {
+	// FirewallRuleProperties - Properties required to create a firewall rule .
+	*FirewallRuleProperties `json:"properties,omitempty"`
+}
+
+// FirewallRuleListResult the response of list firewall rules Redis operation.
+type FirewallRuleListResult struct {
+	rawResponse *http.Response
+	// Value - Results of the list firewall rules operation.
+	Value *[]FirewallRule `json:"value,omitempty"`
+	// NextLink - Link for next page of results.
+	NextLink *string `json:"nextLink,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (frlr FirewallRuleListResult) Response() *http.Response {
+	return frlr.rawResponse
+}
+
+// FirewallRuleListResultPager provides iteration over the pages of FirewallRuleListResult values.
+type FirewallRuleListResultPager struct {
+	*runtime.Pager[*FirewallRuleListResult]
+}
+
+// All returns an iterator over the FirewallRule values in the remaining pages.
+// If an error occurs it is yielded and the iteration stops.
+func (p *FirewallRuleListResultPager) All(ctx context.Context) iter.Seq2[FirewallRule, error] {
+	return runtime.All(ctx, p.Pager, func(frlr *FirewallRuleListResult) []FirewallRule {
+		if frlr.Value == nil {
+			return nil
+		}
+		return *frlr.Value
+	})
+}
+
+// FirewallRuleProperties specifies a range of IP addresses permitted to connect to the cache
+type FirewallRuleProperties struct {
+	// StartIP - lowest IP address included in the range
+	StartIP *string `json:"startIP,omitempty"`
+	// EndIP - highest IP address included in the range
+	EndIP *string `json:"endIP,omitempty"`
+}
+
+// FirewallRulesDeleteResponse ...
+type FirewallRulesDeleteResponse struct {
+	rawResponse *http.Response
+}
+
+// Response returns the raw HTTP response object.
+func (frdr FirewallRulesDeleteResponse) Response() *http.Response {
+	return frdr.rawResponse
+}
+
+// ForceRebootResponse response to force reboot for Redis cache.
+type ForceRebootResponse struct {
+	rawResponse *http.Response
+	// Message - Status message
+	Message *string `json:"message,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (frr ForceRebootResponse) Response() *http.Response {
+	return frr.rawResponse
+}
+
+// ImportDataPoller provides polling facilities until the ImportData operation reaches a terminal state.
+type ImportDataPoller struct {
+	*runtime.Poller[*ImportDataResponse]
+}
+
+// ImportDataResponse ...
+type ImportDataResponse struct {
+	rawResponse *http.Response
+}
+
+// Response returns the raw HTTP response object.
+func (idr ImportDataResponse) Response() *http.Response {
+	return idr.rawResponse
+}
+
+// ImportRDBParameters parameters for Redis import operation.
+type ImportRDBParameters struct {
+	// Format - File format.
+	Format *string `json:"format,omitempty"`
+	// Files - files to import.
+	Files *[]string `json:"files,omitempty"`
+}
+
 // LinkedServer linked server Id
 type LinkedServer struct {
 	// ID - Linked server Id.
 	ID *string `json:"id,omitempty"`
 }
 
+// LinkedServerCreateParameters parameter required for creating a linked server to redis cache.
+type LinkedServerCreateParameters struct {
+	// LinkedServerCreateProperties - Properties required to create a linked server.
+	*LinkedServerCreateProperties `json:"properties,omitempty"`
+}
+
+// LinkedServerCreatePoller provides polling facilities until the linked server Create operation reaches a
+// terminal state. Result and PollUntilDone return the linked server once the operation has succeeded.
+type LinkedServerCreatePoller struct {
+	*runtime.Poller[*LinkedServerWithProperties]
+}
+
+// LinkedServerCreateProperties create properties for a linked server
+type LinkedServerCreateProperties struct {
+	// LinkedRedisCacheID - Fully qualified resourceId of the linked redis cache.
+	LinkedRedisCacheID *string `json:"linkedRedisCacheId,omitempty"`
+	// LinkedRedisCacheLocation - Location of the linked redis cache.
+	LinkedRedisCacheLocation *string `json:"linkedRedisCacheLocation,omitempty"`
+	// ServerRole - Role of the linked server. Possible values include: 'ReplicationRolePrimary', 'ReplicationRoleSecondary'
+	ServerRole ReplicationRole `json:"serverRole,omitempty"`
+}
+
+// LinkedServerProperties properties of a linked server to be returned in get/put response
+type LinkedServerProperties struct {
+	// ProvisioningState - Terminal state of the link between primary and secondary redis cache.
+	ProvisioningState *string `json:"provisioningState,omitempty"`
+	// LinkedRedisCacheID - Fully qualified resourceId of the linked redis cache.
+	LinkedRedisCacheID *string `json:"linkedRedisCacheId,omitempty"`
+	// LinkedRedisCacheLocation - Location of the linked redis cache.
+	LinkedRedisCacheLocation *string `json:"linkedRedisCacheLocation,omitempty"`
+	// ServerRole - Role of the linked server. Possible values include: 'ReplicationRolePrimary', 'ReplicationRoleSecondary'
+	ServerRole ReplicationRole `json:"serverRole,omitempty"`
+}
+
+// LinkedServersDeleteResponse ...
+type LinkedServersDeleteResponse struct {
+	rawResponse *http.Response
+}
+
+// Response returns the raw HTTP response object.
+func (lsdr LinkedServersDeleteResponse) Response() *http.Response {
+	return lsdr.rawResponse
+}
+
+// LinkedServerWithProperties response to put/get linked server (with properties) for Redis cache.
+type LinkedServerWithProperties struct {
+	rawResponse *http.Response
+	// LinkedServerProperties - Properties of the linked server.
+	*LinkedServerProperties `json:"properties,omitempty"`
+	// ID - Resource ID.
+	ID *string `json:"id,omitempty"`
+	// Name - Resource name.
+	Name *string `json:"name,omitempty"`
+	// Type - Resource type.
+	Type *string `json:"type,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (lswp LinkedServerWithProperties) Response() *http.Response {
+	return lswp.rawResponse
+}
+
+// LinkedServerWithPropertiesList list of linked servers (with properties) of a Redis cache.
+type LinkedServerWithPropertiesList struct {
+	rawResponse *http.Response
+	// Value - List of linked servers (with properties) of a Redis cache.
+	Value *[]LinkedServerWithProperties `json:"value,omitempty"`
+	// NextLink - Link for next set.
+	NextLink *string `json:"nextLink,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (lswpl LinkedServerWithPropertiesList) Response() *http.Response {
+	return lswpl.rawResponse
+}
+
+// LinkedServerWithPropertiesListPager provides iteration over the pages of LinkedServerWithPropertiesList values.
+type LinkedServerWithPropertiesListPager struct {
+	*runtime.Pager[*LinkedServerWithPropertiesList]
+}
+
+// All returns an iterator over the LinkedServerWithProperties values in the remaining pages.
+// If an error occurs it is yielded and the iteration stops.
+func (p *LinkedServerWithPropertiesListPager) All(ctx context.Context) iter.Seq2[LinkedServerWithProperties, error] {
+	return runtime.All(ctx, p.Pager, func(lswpl *LinkedServerWithPropertiesList) []LinkedServerWithProperties {
+		if lswpl.Value == nil {
+			return nil
+		}
+		return *lswpl.Value
+	})
+}
+
+// ListResult the response of list Redis operation.
+type ListResult struct {
+	rawResponse *http.Response
+	// Value - List of Redis cache instances.
+	Value *[]ResourceType `json:"value,omitempty"`
+	// NextLink - Link for next page of results.
+	NextLink *string `json:"nextLink,omitempty"`
+}
+
+// Response returns the raw HTTP response object.
+func (lr ListResult) Response() *http.Response {
+	return lr.rawResponse
+}
+
+// ListResultPager provides iteration over the pages of ListResult values.
+type ListResultPager struct {
+	*runtime.Pager[*ListResult]
+}
+
+// All returns an iterator over the ResourceType values in the remaining pages.
+// If an error occurs it is yielded and the iteration stops.
+func (p *ListResultPager) All(ctx context.Context) iter.Seq2[ResourceType, error] {
+	return runtime.All(ctx, p.Pager, func(lr *ListResult) []ResourceType {
+		if lr.Value == nil {
+			return nil
+		}
+		return *lr.Value
+	})
+}
+
 // Operation REST API operation
 type Operation struct {
 	// Name - Operation name: {provider}/{resource}/{operation}
@@ -140,104 +402,60 @@ type OperationDisplay struct {
 // OperationListResult result of the request to list REST API operations. It contains a list of operations and a
 // URL nextLink to get the next set of results.
 type OperationListResult struct {
-	autorest.Response `json:"-"`
+	rawResponse *http.Response
 	// Value - List of operations supported by the resource provider.
 	Value *[]Operation `json:"value,omitempty"`
 	// NextLink - URL to get the next set of operation list results if there are any.
 	NextLink *string `json:"nextLink,omitempty"`
 }
 
-// OperationListResultIterator provides access to a complete listing of Operation values.
-type OperationListResultIterator struct {
-	i    int
-	page OperationListResultPage
-}
-
-// Next advances to the next value.  If there was an error making
-// the request the iterator does not advance and the error is returned.
-func (iter *OperationListResultIterator) Next() error {
-	iter.i++
-	if iter.i < len(iter.page.Values()) {
-		return nil
-	}
-	err := iter.page.Next()
-	if err != nil {
-		iter.i--
-		return err
-	}
-	iter.i = 0
-	return nil
-}
-
-// NotDone returns true if the enumeration should be started or is not yet complete.
-func (iter OperationListResultIterator) NotDone() bool {
-	return iter.page.NotDone() && iter.i < len(iter.page.Values())
-}
-
-// Response returns the raw server response from the last page request.
-func (iter OperationListResultIterator) Response() OperationListResult {
-	return iter.page.Response()
-}
-
-// Value returns the current value or a zero-initialized value if the
-// iterator has advanced beyond the end of the collection.
-func (iter OperationListResultIterator) Value() Operation {
-	if !iter.page.NotDone() {
-		return Operation{}
-	}
-	return iter.page.Values()[iter.i]
-}
-
-// IsEmpty returns true if the ListResult contains no values.
-func (olr OperationListResult) IsEmpty() bool {
-	return olr.Value == nil || len(*olr.Value) == 0
+// Response returns the raw HTTP response object.
+func (olr OperationListResult) Response() *http.Response {
+	return olr.rawResponse
 }
 
-// operationListResultPreparer prepares a request to retrieve the next set of results.
-// It returns nil if no more results exist.
-func (olr OperationListResult) operationListResultPreparer() (*http.Request, error) {
-	if olr.NextLink == nil || len(*olr.NextLink) < 1 {
-		return nil, nil
-	}
-	return autorest.Prepare(&http.Request{},
-		autorest.AsJSON(),
-		autorest.AsGet(),
-		autorest.WithBaseURL(*olr.NextLink))
+// OperationListResultPager provides iteration over the pages of OperationListResult values.
+type OperationListResultPager struct {
+	*runtime.Pager[*OperationListResult]
 }
 
-// OperationListResultPage contains a page of Operation values.
-type OperationListResultPage struct {
-	fn  func(OperationListResult) (OperationListResult, error)
-	olr OperationListResult
+// All returns an iterator over the Operation values in the remaining pages.
+// If an error occurs it is yielded and the iteration stops.
+func (p *OperationListResultPager) All(ctx context.Context) iter.Seq2[Operation, error] {
+	return runtime.All(ctx, p.Pager, func(olr *OperationListResult) []Operation {
+		if olr.Value == nil {
+			return nil
+		}
+		return *olr.Value
+	})
 }
 
-// Next advances to the next page of values.  If there was an error making
-// the request the page does not advance and the error is returned.
-func (page *OperationListResultPage) Next() error {
-	next, err := page.fn(page.olr)
-	if err != nil {
-		return err
-	}
-	page.olr = next
-	return nil
+// PatchSchedule response to put/get patch schedules for Redis cache.
+type PatchSchedule struct {
+	rawResponse *http.Response
+	// ScheduleEntries - List of patch schedules for a Redis cache.
+	*ScheduleEntries `json:"properties,omitempty"`
+	// ID - Resource ID.
+	ID *string `json:"id,omitempty"`
+	// Name - Resource name.
+	Name *string `json:"name,omitempty"`
+	// Type - Resource type.
+	Type *string `json:"type,omitempty"`
 }
 
-// NotDone returns true if the page enumeration should be started or is not yet complete.
-func (page OperationListResultPage) NotDone() bool {
-	return !page.olr.IsEmpty()
+// Response returns the raw HTTP response object.
+func (ps PatchSchedule) Response() *http.Response {
+	return ps.rawResponse
 }
 
-// Response returns the raw server response from the last page request.
-func (page OperationListResultPage) Response() OperationListResult {
-	return page.olr
+// PatchSchedulesDeleteResponse ...
+type PatchSchedulesDeleteResponse struct {
+	rawResponse *http.Response
 }
 
-// Values returns the slice of values for the current page or nil if there are no values.
-func (page OperationListResultPage) Values() []Operation {
-	if page.olr.IsEmpty() {
-		return nil
-	}
-	return *page.olr.Value
+// Response returns the raw HTTP response object.
+func (psdr PatchSchedulesDeleteResponse) Response() *http.Response {
+	return psdr.rawResponse
 }
 
 // Properties properties of the redis cache.
@@ -274,6 +492,20 @@ type Properties struct {
 	MinimumTLSVersion TLSVersion `json:"minimumTlsVersion,omitempty"`
 }
 
+// RebootParameters specifies which Redis node(s) to reboot.
+type RebootParameters struct {
+	// RebootType - Which Redis node(s) to reboot. Depending on this value data loss is possible. Possible values include: 'PrimaryNode', 'SecondaryNode', 'AllNodes'
+	RebootType RebootType `json:"rebootType,omitempty"`
+	// ShardID - If clustering is enabled, the ID of the shard to be rebooted.
+	ShardID *int32 `json:"shardId,omitempty"`
+}
+
+// RegenerateKeyParameters specifies which Redis access keys to reset.
+type RegenerateKeyParameters struct {
+	// KeyType - The Redis access key to regenerate. Possible values include: 'Primary', 'Secondary'
+	KeyType KeyType `json:"keyType,omitempty"`
+}
+
 // ResourceType a single Redis item in List or Get Operation.
 type ResourceType struct {
 	rawResponse *http.Response
@@ -298,6 +530,75 @@ func (rt ResourceType) Response() *http.Response {
 	return rt.rawResponse
 }
 
+// ScheduleEntries list of patch schedules for a Redis cache.
+type ScheduleEntries struct {
+	// ScheduleEntries - List of patch schedules for a Redis cache.
+	ScheduleEntries *[]ScheduleEntry `json:"scheduleEntries,omitempty"`
+}
+
+// ScheduleEntry patch schedule entry for a Premium Redis Cache.
+type ScheduleEntry struct {
+	// DayOfWeek - Day of the week when a cache can be patched. Possible values include: 'Monday', 'Tuesday', 'Wednesday', 'Thursday', 'Friday', 'Saturday', 'Sunday', 'Everyday', 'Weekend'
+	DayOfWeek DayOfWeek `json:"dayOfWeek,omitempty"`
+	// StartHourUtc - Start hour after which cache patching can start.
+	StartHourUtc *int32 `json:"startHourUtc,omitempty"`
+	// MaintenanceWindow - The length of the maintenance window; sent to the service as an ISO8601 timespan.
+	MaintenanceWindow *time.Duration `json:"maintenanceWindow,omitempty"`
+}
+
+// MarshalJSON is the custom marshaler for ScheduleEntry.
+func (se ScheduleEntry) MarshalJSON() ([]byte, error) {
+	objectMap := make(map[string]interface{})
+	if se.DayOfWeek != "" {
+		objectMap["dayOfWeek"] = se.DayOfWeek
+	}
+	if se.StartHourUtc != nil {
+		objectMap["startHourUtc"] = se.StartHourUtc
+	}
+	if se.MaintenanceWindow != nil {
+		objectMap["maintenanceWindow"] = runtime.FormatDuration(*se.MaintenanceWindow)
+	}
+	return json.Marshal(objectMap)
+}
+
+// UnmarshalJSON is the custom unmarshaler for ScheduleEntry.
+func (se *ScheduleEntry) UnmarshalJSON(body []byte) error {
+	var m map[string]*json.RawMessage
+	if err := json.Unmarshal(body, &m); err != nil {
+		return err
+	}
+	for k, v := range m {
+		if v == nil {
+			continue
+		}
+		switch k {
+		case "dayOfWeek":
+			var dayOfWeek DayOfWeek
+			if err := json.Unmarshal(*v, &dayOfWeek); err != nil {
+				return err
+			}
+			se.DayOfWeek = dayOfWeek
+		case "startHourUtc":
+			var startHourUtc int32
+			if err := json.Unmarshal(*v, &startHourUtc); err != nil {
+				return err
+			}
+			se.StartHourUtc = &startHourUtc
+		case "maintenanceWindow":
+			var maintenanceWindow string
+			if err := json.Unmarshal(*v, &maintenanceWindow); err != nil {
+				return err
+			}
+			d, err := runtime.ParseDuration(maintenanceWindow)
+			if err != nil {
+				return err
+			}
+			se.MaintenanceWindow = &d
+		}
+	}
+	return nil
+}
+
 // Sku SKU parameters supplied to the create Redis operation.
 type Sku struct {
 	// Name - The type of Redis cache to deploy. Valid values: (Basic, Standard, Premium). Possible values include: 'Basic', 'Standard', 'Premium'
@@ -307,3 +608,27 @@ type Sku struct {
 	// Capacity - The size of the Redis cache to deploy. Valid values: for C (Basic/Standard) family (0, 1, 2, 3, 4, 5, 6), for P (Premium) family (1, 2, 3, 4).
 	Capacity *int32 `json:"capacity,omitempty"`
 }
+
+// UpdateParameters parameters supplied to the Update Redis operation.
+type UpdateParameters struct {
+	// UpdateProperties - Redis cache properties.
+	*UpdateProperties `json:"properties,omitempty"`
+	// Tags - Resource tags.
+	Tags map[string]*string `json:"tags,omitempty"`
+}
+
+// UpdateProperties patchable properties of the redis cache.
+type UpdateProperties struct {
+	// Sku - The SKU of the Redis cache to deploy.
+	Sku *Sku `json:"sku,omitempty"`
+	// RedisConfiguration - All Redis Settings. Few possible keys: rdb-backup-enabled,rdb-storage-connection-string,rdb-backup-frequency,maxmemory-delta,maxmemory-policy,notify-keyspace-events,maxmemory-samples,slowlog-log-slower-than,slowlog-max-len,list-max-ziplist-entries,list-max-ziplist-value,hash-max-ziplist-entries,hash-max-ziplist-value,set-max-intset-entries,zset-max-ziplist-entries,zset-max-ziplist-value etc.
+	RedisConfiguration map[string]*string `json:"redisConfiguration,omitempty"`
+	// EnableNonSslPort - Specifies whether the non-ssl Redis server port (6379) is enabled.
+	EnableNonSslPort *bool `json:"enableNonSslPort,omitempty"`
+	// TenantSettings - A dictionary of tenant settings
+	TenantSettings map[string]*string `json:"tenantSettings,omitempty"`
+	// ShardCount - The number of shards to be created on a Premium Cluster Cache.
+	ShardCount *int32 `json:"shardCount,omitempty"`
+	// MinimumTLSVersion - Optional: requires clients to use a specified TLS version (or higher) to connect (e,g, '1.0', '1.1', '1.2'). Possible values include: 'OneFullStopZero', 'OneFullStopOne', 'OneFullStopTwo'
+	MinimumTLSVersion TLSVersion `json:"minimumTlsVersion,omitempty"`
+}
@@ -20,10 +20,13 @@ package redis
 import (
 	"context"
 	"net/http"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
 )
 
 type IOperations interface {
-	List(ctx context.Context) (*OperationListResultPage, error)
+	List() *OperationListResultPager
 }
 
 // OperationsClient is the REST API for Azure Redis Cache Service.
@@ -32,81 +35,37 @@ type operationsClient struct {
 }
 
 // List lists all of the available REST API operations of the Microsoft.Cache provider.
-func (c operationsClient) List(ctx context.Context) (result *OperationListResultPage, err error) {
-	/*result.fn = client.listNextResults
-	req, err := client.ListPreparer(ctx)
-	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.OperationsClient", "List", nil, "Failure preparing request")
-		return
-	}
-
-	resp, err := client.ListSender(req)
-	if err != nil {
-		result.olr.Response = autorest.Response{Response: resp}
-		err = autorest.NewErrorWithError(err, "redis.OperationsClient", "List", resp, "Failure sending request")
-		return
-	}
-
-	result.olr, err = client.ListResponder(resp)
-	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.OperationsClient", "List", resp, "Failure responding to request")
-	}*/
-
-	return
+func (c operationsClient) List() *OperationListResultPager {
+	return &OperationListResultPager{runtime.NewPager(c.p, c.listPreparer, c.listResponder, func(olr *OperationListResult) *string {
+		return olr.NextLink
+	})}
 }
 
 // ListPreparer prepares the List request.
-func (c operationsClient) listPreparer(ctx context.Context) (*http.Request, error) {
-	/*const APIVersion = "2018-03-01"
-	queryParameters := map[string]interface{}{
-		"api-version": APIVersion,
+func (c operationsClient) listPreparer(ctx context.Context) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = "/providers/Microsoft.Cache/operations"
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
 	}
-
-	preparer := autorest.CreatePreparer(
-		autorest.AsGet(),
-		autorest.WithBaseURL(client.BaseURI),
-		autorest.WithPath("/providers/Microsoft.Cache/operations"),
-		autorest.WithQueryParameters(queryParameters))
-	return preparer.Prepare((&http.Request{}).WithContext(ctx))*/
-	return nil, nil
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
 }
 
 // ListResponder handles the response to the List request. The method always
 // closes the http.Response Body.
-func (c operationsClient) listResponder(resp *http.Response) (result OperationListResult, err error) {
-	/*err = autorest.Respond(
-		resp,
-		client.ByInspecting(),
-		azure.WithErrorUnlessStatusCode(http.StatusOK),
-		autorest.ByUnmarshallingJSON(&result),
-		autorest.ByClosing())
-	result.Response = autorest.Response{Response: resp}*/
-	return
-}
-
-// listNextResults retrieves the next set of results, if any.
-func (c operationsClient) listNextResults(lastResults OperationListResult) (result OperationListResult, err error) {
-	/*req, err := lastResults.operationListResultPreparer()
-	if err != nil {
-		return result, autorest.NewErrorWithError(err, "redis.OperationsClient", "listNextResults", nil, "Failure preparing next results request")
+func (c operationsClient) listResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
 	}
-	if req == nil {
-		return
-	}
-	resp, err := client.ListSender(req)
+	result := &OperationListResult{rawResponse: resp.Response()}
 	if err != nil {
-		result.Response = autorest.Response{Response: resp}
-		return result, autorest.NewErrorWithError(err, "redis.OperationsClient", "listNextResults", resp, "Failure sending next results request")
+		return result, err
 	}
-	result, err = client.ListResponder(resp)
-	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.OperationsClient", "listNextResults", resp, "Failure responding to next results request")
-	}*/
-	return
-}
-
-// ListComplete enumerates all values, automatically crossing page boundaries as required.
-func (c operationsClient) ListComplete(ctx context.Context) (result OperationListResultIterator, err error) {
-	//result.page, err = client.List(ctx)
-	return
+	return result, runtime.FromJSON(resp, result)
 }
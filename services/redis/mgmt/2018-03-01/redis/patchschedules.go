@@ -0,0 +1,197 @@
+package redis
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Code generated by Microsoft (R) AutoRest Code Generator.
+// Changes may cause incorrect behavior and will be lost if the code is regenerated.
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/validation"
+)
+
+type IPatchSchedules interface {
+	CreateOrUpdate(ctx context.Context, resourceGroupName string, name string, parameters PatchSchedule) (*PatchSchedule, error)
+	Delete(ctx context.Context, resourceGroupName string, name string) (*PatchSchedulesDeleteResponse, error)
+	Get(ctx context.Context, resourceGroupName string, name string) (*PatchSchedule, error)
+}
+
+// PatchSchedulesClient is the REST API for Azure Redis Cache Service.
+type patchSchedulesClient struct {
+	*Client
+}
+
+// CreateOrUpdate create or replace the patching schedule for Redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// parameters - parameters to set the patching schedule for Redis cache.
+func (c patchSchedulesClient) CreateOrUpdate(ctx context.Context, resourceGroupName string, name string, parameters PatchSchedule) (*PatchSchedule, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.ScheduleEntries", Name: validation.Null, Rule: true,
+				Chain: []validation.Constraint{{Target: "parameters.ScheduleEntries.ScheduleEntries", Name: validation.Null, Rule: true, Chain: nil}}}}}}); err != nil {
+		return nil, validation.NewError("redis.PatchSchedulesClient", "CreateOrUpdate", "%s", err.Error())
+	}
+	req, err := c.createOrUpdatePreparer(ctx, resourceGroupName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.createOrUpdateResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*PatchSchedule), err
+}
+
+// CreateOrUpdatePreparer prepares the CreateOrUpdate request.
+func (c patchSchedulesClient) createOrUpdatePreparer(ctx context.Context, resourceGroupName string, name string, parameters PatchSchedule) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/patchSchedules/default", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPut, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// CreateOrUpdateResponder handles the response to the CreateOrUpdate request. The method always
+// closes the http.Response Body.
+func (c patchSchedulesClient) createOrUpdateResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated)
+	if resp == nil {
+		return nil, err
+	}
+	result := &PatchSchedule{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// Delete deletes the patching schedule of a redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the redis cache.
+func (c patchSchedulesClient) Delete(ctx context.Context, resourceGroupName string, name string) (*PatchSchedulesDeleteResponse, error) {
+	req, err := c.deletePreparer(ctx, resourceGroupName, name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.deleteResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*PatchSchedulesDeleteResponse), err
+}
+
+// DeletePreparer prepares the Delete request.
+func (c patchSchedulesClient) deletePreparer(ctx context.Context, resourceGroupName string, name string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/patchSchedules/default", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// DeleteResponder handles the response to the Delete request. The method always
+// closes the http.Response Body.
+func (c patchSchedulesClient) deleteResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &PatchSchedulesDeleteResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, resp.Response().Body.Close()
+}
+
+// Get gets the patching schedule of a redis cache (requires Premium SKU).
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the redis cache.
+func (c patchSchedulesClient) Get(ctx context.Context, resourceGroupName string, name string) (*PatchSchedule, error) {
+	req, err := c.getPreparer(ctx, resourceGroupName, name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.getResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*PatchSchedule), err
+}
+
+// GetPreparer prepares the Get request.
+func (c patchSchedulesClient) getPreparer(ctx context.Context, resourceGroupName string, name string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/patchSchedules/default", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// GetResponder handles the response to the Get request. The method always
+// closes the http.Response Body.
+func (c patchSchedulesClient) getResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &PatchSchedule{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
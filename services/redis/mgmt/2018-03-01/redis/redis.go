@@ -28,7 +28,17 @@ import (
 
 type IRedis interface {
 	CheckNameAvailability(ctx context.Context, parameters CheckNameAvailabilityParameters) (*CheckNameAvailabilityResponse, error)
+	Create(ctx context.Context, resourceGroupName string, name string, parameters CreateParameters) (*CreatePoller, error)
+	Delete(ctx context.Context, resourceGroupName string, name string) (*DeletePoller, error)
+	ExportData(ctx context.Context, resourceGroupName string, name string, parameters ExportRDBParameters) (*ExportDataPoller, error)
+	ForceReboot(ctx context.Context, resourceGroupName string, name string, parameters RebootParameters) (*ForceRebootResponse, error)
 	Get(ctx context.Context, resourceGroupName string, name string) (*ResourceType, error)
+	ImportData(ctx context.Context, resourceGroupName string, name string, parameters ImportRDBParameters) (*ImportDataPoller, error)
+	ListByResourceGroup(resourceGroupName string) *ListResultPager
+	ListBySubscription() *ListResultPager
+	ListKeys(ctx context.Context, resourceGroupName string, name string) (*AccessKeys, error)
+	RegenerateKey(ctx context.Context, resourceGroupName string, name string, keyType KeyType) (*AccessKeys, error)
+	Update(ctx context.Context, resourceGroupName string, name string, parameters UpdateParameters) (*ResourceType, error)
 }
 
 // Client is the REST API for Azure Redis Cache Service.
@@ -37,6 +47,8 @@ type client struct {
 }
 
 // CheckNameAvailability checks that the redis cache name is valid and is not already in use.
+// A name that is already in use is reported with NameAvailable set to false and Reason
+// set to AlreadyExists rather than as an error.
 // Parameters:
 // parameters - parameters supplied to the CheckNameAvailability Redis operation. The only supported resource
 // type is 'Microsoft.Cache/redis'
@@ -45,7 +57,7 @@ func (c client) CheckNameAvailability(ctx context.Context, parameters CheckNameA
 		{TargetValue: parameters,
 			Constraints: []validation.Constraint{{Target: "parameters.Name", Name: validation.Null, Rule: true, Chain: nil},
 				{Target: "parameters.Type", Name: validation.Null, Rule: true, Chain: nil}}}}); err != nil {
-		return nil, validation.NewError("redis.Client", "CheckNameAvailability", err.Error())
+		return nil, validation.NewError("redis.Client", "CheckNameAvailability", "%s", err.Error())
 	}
 	req, err := c.checkNameAvailabilityPreparer(ctx, parameters)
 	if err != nil {
@@ -80,14 +92,50 @@ func (c client) checkNameAvailabilityPreparer(ctx context.Context, parameters Ch
 	return req, nil
 }
 
+// nameNotAvailableCode is the error code the service returns with a 409 when a cache name is already in use.
+const nameNotAvailableCode = "NameNotAvailable"
+
 // CheckNameAvailabilityResponder handles the response to the CheckNameAvailability request. The method always
 // closes the http.Response Body.
 func (c client) checkNameAvailabilityResponder(resp pipeline.Response) (pipeline.Response, error) {
-	err := runtime.ValidateResponse(resp, http.StatusOK)
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusConflict)
 	if resp == nil {
 		return nil, err
 	}
-	return &CheckNameAvailabilityResponse{rawResponse: resp.Response()}, err
+	result := &CheckNameAvailabilityResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	if resp.Response().StatusCode == http.StatusConflict {
+		// the service reports a name that's in use as a conflict with an error body,
+		// any other conflict is a genuine failure
+		var body struct {
+			Error struct {
+				Code    string  `json:"code"`
+				Message *string `json:"message"`
+			} `json:"error"`
+		}
+		if err = runtime.FromJSON(resp, &body); err != nil {
+			return result, err
+		}
+		if body.Error.Code != nameNotAvailableCode {
+			return result, runtime.NewResponseError(nil, resp.Response(), resp.Response().Status)
+		}
+		available := false
+		result.NameAvailable = &available
+		result.Reason = AlreadyExists
+		result.Message = body.Error.Message
+		return result, nil
+	}
+	if err = runtime.FromJSON(resp, result); err != nil {
+		return result, err
+	}
+	if result.NameAvailable == nil {
+		// a successful response without a body means the name is available
+		available := true
+		result.NameAvailable = &available
+	}
+	return result, nil
 }
 
 // Create create or replace (overwrite/recreate, with potential downtime) an existing Redis cache.
@@ -95,94 +143,290 @@ func (c client) checkNameAvailabilityResponder(resp pipeline.Response) (pipeline
 // resourceGroupName - the name of the resource group.
 // name - the name of the Redis cache.
 // parameters - parameters supplied to the Create Redis operation.
-/*func (c client) Create(ctx context.Context, resourceGroupName string, name string, parameters CreateParameters) (result CreateFuture, err error) {
+func (c client) Create(ctx context.Context, resourceGroupName string, name string, parameters CreateParameters) (*CreatePoller, error) {
 	if err := validation.Validate([]validation.Validation{
 		{TargetValue: parameters,
 			Constraints: []validation.Constraint{{Target: "parameters.CreateProperties", Name: validation.Null, Rule: true,
 				Chain: []validation.Constraint{{Target: "parameters.CreateProperties.Sku", Name: validation.Null, Rule: true,
 					Chain: []validation.Constraint{{Target: "parameters.CreateProperties.Sku.Capacity", Name: validation.Null, Rule: true, Chain: nil}}},
 					{Target: "parameters.CreateProperties.SubnetID", Name: validation.Null, Rule: false,
-						Chain: []validation.Constraint{{Target: "parameters.CreateProperties.SubnetID", Name: validation.Pattern, Rule: `^/subscriptions/[^/]* /resourceGroups/[^/]* /providers/Microsoft.(ClassicNetwork|Network)/virtualNetworks/[^/]* /subnets/[^/]*$`, Chain: nil}}},
+						Chain: []validation.Constraint{{Target: "parameters.CreateProperties.SubnetID", Name: validation.Pattern, Rule: `^/subscriptions/[^/]*/resourceGroups/[^/]*/providers/Microsoft.(ClassicNetwork|Network)/virtualNetworks/[^/]*/subnets/[^/]*$`, Chain: nil}}},
 					{Target: "parameters.CreateProperties.StaticIP", Name: validation.Null, Rule: false,
 						Chain: []validation.Constraint{{Target: "parameters.CreateProperties.StaticIP", Name: validation.Pattern, Rule: `^\d+\.\d+\.\d+\.\d+$`, Chain: nil}}},
 				}},
 				{Target: "parameters.Location", Name: validation.Null, Rule: true, Chain: nil}}}}); err != nil {
-		return result, validation.NewError("redis.Client", "Create", err.Error())
+		return nil, validation.NewError("redis.Client", "Create", "%s", err.Error())
+	}
+	req, err := c.createPreparer(ctx, resourceGroupName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[*ResourceType]("redis.Create", c.p, resp, c.createResponder)
+	if err != nil {
+		return nil, err
 	}
+	return &CreatePoller{poller}, nil
+}
 
-	req, err := client.createPreparer(ctx, resourceGroupName, name, parameters)
+// ResumeCreate creates a CreatePoller from a token obtained from CreatePoller.ResumeToken,
+// allowing an in-flight Create operation to be polled by a different process.
+func (c Client) ResumeCreate(token string) (*CreatePoller, error) {
+	poller, err := runtime.NewPollerFromResumeToken[*ResourceType]("redis.Create", c.p, token, client{&c}.createResponder)
 	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.Client", "Create", nil, "Failure preparing request")
-		return
+		return nil, err
 	}
+	return &CreatePoller{poller}, nil
+}
 
-	result, err = client.createSender(req)
+// CreatePreparer prepares the Create request.
+func (c client) createPreparer(ctx context.Context, resourceGroupName string, name string, parameters CreateParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPut, u, b)
 	if err != nil {
-		err = autorest.NewErrorWithError(err, "redis.Client", "Create", result.Response(), "Failure sending request")
-		return
+		return req, pipeline.NewError(err, "failed to create request")
 	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
 
-	return
+// CreateResponder handles the final response to the Create request. The method always
+// closes the http.Response Body.
+func (c client) createResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated)
+	if resp == nil {
+		return nil, err
+	}
+	result := &ResourceType{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
 }
 
-// CreatePreparer prepares the Create request.
-func (c client) createPreparer(ctx context.Context, resourceGroupName string, name string, parameters CreateParameters) (*http.Request, error) {
-	pathParameters := map[string]interface{}{
-		"name":              autorest.Encode("path", name),
-		"resourceGroupName": autorest.Encode("path", resourceGroupName),
-		"subscriptionId":    autorest.Encode("path", client.SubscriptionID),
-	}
-
-	const APIVersion = "2018-03-01"
-	queryParameters := map[string]interface{}{
-		"api-version": APIVersion,
-	}
-
-	preparer := autorest.CreatePreparer(
-		autorest.AsContentType("application/json; charset=utf-8"),
-		autorest.AsPut(),
-		autorest.WithBaseURL(client.BaseURI),
-		autorest.WithPathParameters("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}", pathParameters),
-		autorest.WithJSON(parameters),
-		autorest.WithQueryParameters(queryParameters))
-	return preparer.Prepare((&http.Request{}).WithContext(ctx))
+// Delete deletes a Redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+func (c client) Delete(ctx context.Context, resourceGroupName string, name string) (*DeletePoller, error) {
+	req, err := c.deletePreparer(ctx, resourceGroupName, name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.ValidateResponse(resp, http.StatusOK, http.StatusAccepted, http.StatusNoContent); err != nil {
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[*DeleteResponse]("redis.Delete", c.p, resp, c.deleteResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &DeletePoller{poller}, nil
+}
+
+// ResumeDelete creates a DeletePoller from a token obtained from DeletePoller.ResumeToken,
+// allowing an in-flight Delete operation to be polled by a different process.
+func (c Client) ResumeDelete(token string) (*DeletePoller, error) {
+	poller, err := runtime.NewPollerFromResumeToken[*DeleteResponse]("redis.Delete", c.p, token, client{&c}.deleteResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &DeletePoller{poller}, nil
+}
+
+// DeletePreparer prepares the Delete request.
+func (c client) deletePreparer(ctx context.Context, resourceGroupName string, name string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// DeleteResponder handles the final response to the Delete request. The method always
+// closes the http.Response Body.
+func (c client) deleteResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &DeleteResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, resp.Response().Body.Close()
+}
+
+// ExportData export data from the redis cache to blobs in a container.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// parameters - parameters for Redis export operation.
+func (c client) ExportData(ctx context.Context, resourceGroupName string, name string, parameters ExportRDBParameters) (*ExportDataPoller, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.Prefix", Name: validation.Null, Rule: true, Chain: nil},
+				{Target: "parameters.Container", Name: validation.Null, Rule: true, Chain: nil}}}}); err != nil {
+		return nil, validation.NewError("redis.Client", "ExportData", "%s", err.Error())
+	}
+	req, err := c.exportDataPreparer(ctx, resourceGroupName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.ValidateResponse(resp, http.StatusOK, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[*ExportDataResponse]("redis.ExportData", c.p, resp, c.exportDataResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportDataPoller{poller}, nil
+}
+
+// ResumeExportData creates a ExportDataPoller from a token obtained from ExportDataPoller.ResumeToken,
+// allowing an in-flight ExportData operation to be polled by a different process.
+func (c Client) ResumeExportData(token string) (*ExportDataPoller, error) {
+	poller, err := runtime.NewPollerFromResumeToken[*ExportDataResponse]("redis.ExportData", c.p, token, client{&c}.exportDataResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportDataPoller{poller}, nil
+}
+
+// ExportDataPreparer prepares the ExportData request.
+func (c client) exportDataPreparer(ctx context.Context, resourceGroupName string, name string, parameters ExportRDBParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/export", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPost, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
 }
 
-// CreateSender sends the Create request. The method will close the
-// http.Response Body if it receives an error.
-func (c client) createSender(req *http.Request) (future CreateFuture, err error) {
-	var resp *http.Response
-	resp, err = autorest.SendWithSender(client, req,
-		azure.DoRetryWithRegistration(client.Client))
+// ExportDataResponder handles the final response to the ExportData request. The method always
+// closes the http.Response Body.
+func (c client) exportDataResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &ExportDataResponse{rawResponse: resp.Response()}
 	if err != nil {
-		return
+		return result, err
 	}
-	err = autorest.Respond(resp, azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated))
+	return result, resp.Response().Body.Close()
+}
+
+// ForceReboot reboot specified Redis node(s). This operation requires write permission to the cache resource. There
+// can be potential data loss.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// parameters - specifies which Redis node(s) to reboot.
+func (c client) ForceReboot(ctx context.Context, resourceGroupName string, name string, parameters RebootParameters) (*ForceRebootResponse, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.RebootType", Name: validation.Empty, Rule: true, Chain: nil}}}}); err != nil {
+		return nil, validation.NewError("redis.Client", "ForceReboot", "%s", err.Error())
+	}
+	req, err := c.forceRebootPreparer(ctx, resourceGroupName, name, parameters)
 	if err != nil {
-		return
+		return nil, err
 	}
-	future.Future, err = azure.NewFutureFromResponse(resp)
-	return
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.forceRebootResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*ForceRebootResponse), err
+}
+
+// ForceRebootPreparer prepares the ForceReboot request.
+func (c client) forceRebootPreparer(ctx context.Context, resourceGroupName string, name string, parameters RebootParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/forceReboot", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPost, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
 }
 
-// CreateResponder handles the response to the Create request. The method always
+// ForceRebootResponder handles the response to the ForceReboot request. The method always
 // closes the http.Response Body.
-func (c client) createResponder(resp pipeline.Response) (*ResourceType, error) {
-	/*err = autorest.Respond(
-		resp,
-		client.ByInspecting(),
-		azure.WithErrorUnlessStatusCode(http.StatusOK, http.StatusCreated),
-		autorest.ByUnmarshallingJSON(&result),
-		autorest.ByClosing())
-	result.Response = autorest.Response{Response: resp}
-	return
-	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusCreated)
+func (c client) forceRebootResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
 	if resp == nil {
 		return nil, err
 	}
-	return &ResourceType{rawResponse: resp.Response()}, err
-}*/
+	result := &ForceRebootResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
 
 // Get gets a Redis cache (resource description).
 // Parameters:
@@ -232,3 +476,312 @@ func (c client) getResponder(resp pipeline.Response) (pipeline.Response, error)
 	}
 	return result, runtime.FromJSON(resp, result)
 }
+
+// ImportData import data into Redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// parameters - parameters for Redis import operation.
+func (c client) ImportData(ctx context.Context, resourceGroupName string, name string, parameters ImportRDBParameters) (*ImportDataPoller, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: parameters,
+			Constraints: []validation.Constraint{{Target: "parameters.Files", Name: validation.Null, Rule: true, Chain: nil}}}}); err != nil {
+		return nil, validation.NewError("redis.Client", "ImportData", "%s", err.Error())
+	}
+	req, err := c.importDataPreparer(ctx, resourceGroupName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = runtime.ValidateResponse(resp, http.StatusOK, http.StatusAccepted); err != nil {
+		return nil, err
+	}
+	poller, err := runtime.NewPoller[*ImportDataResponse]("redis.ImportData", c.p, resp, c.importDataResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportDataPoller{poller}, nil
+}
+
+// ResumeImportData creates a ImportDataPoller from a token obtained from ImportDataPoller.ResumeToken,
+// allowing an in-flight ImportData operation to be polled by a different process.
+func (c Client) ResumeImportData(token string) (*ImportDataPoller, error) {
+	poller, err := runtime.NewPollerFromResumeToken[*ImportDataResponse]("redis.ImportData", c.p, token, client{&c}.importDataResponder)
+	if err != nil {
+		return nil, err
+	}
+	return &ImportDataPoller{poller}, nil
+}
+
+// ImportDataPreparer prepares the ImportData request.
+func (c client) importDataPreparer(ctx context.Context, resourceGroupName string, name string, parameters ImportRDBParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/import", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPost, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ImportDataResponder handles the final response to the ImportData request. The method always
+// closes the http.Response Body.
+func (c client) importDataResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK, http.StatusNoContent)
+	if resp == nil {
+		return nil, err
+	}
+	result := &ImportDataResponse{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, resp.Response().Body.Close()
+}
+
+// ListByResourceGroup lists all Redis caches in a resource group.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+func (c client) ListByResourceGroup(resourceGroupName string) *ListResultPager {
+	return &ListResultPager{runtime.NewPager(c.p, func(ctx context.Context) (pipeline.Request, error) {
+		return c.listByResourceGroupPreparer(ctx, resourceGroupName)
+	}, c.listResponder, func(lr *ListResult) *string {
+		return lr.NextLink
+	})}
+}
+
+// ListByResourceGroupPreparer prepares the ListByResourceGroup request.
+func (c client) listByResourceGroupPreparer(ctx context.Context, resourceGroupName string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/", map[string]string{
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ListBySubscription gets all Redis caches in the specified subscription.
+func (c client) ListBySubscription() *ListResultPager {
+	return &ListResultPager{runtime.NewPager(c.p, c.listBySubscriptionPreparer, c.listResponder, func(lr *ListResult) *string {
+		return lr.NextLink
+	})}
+}
+
+// ListBySubscriptionPreparer prepares the ListBySubscription request.
+func (c client) listBySubscriptionPreparer(ctx context.Context) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/providers/Microsoft.Cache/Redis/", map[string]string{
+		"subscriptionId": c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ListResponder handles the response to the ListByResourceGroup and ListBySubscription requests.
+// The method always closes the http.Response Body.
+func (c client) listResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &ListResult{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// ListKeys retrieve a Redis cache's access keys. This operation requires write permission to the cache resource.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+func (c client) ListKeys(ctx context.Context, resourceGroupName string, name string) (*AccessKeys, error) {
+	req, err := c.listKeysPreparer(ctx, resourceGroupName, name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.listKeysResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*AccessKeys), err
+}
+
+// ListKeysPreparer prepares the ListKeys request.
+func (c client) listKeysPreparer(ctx context.Context, resourceGroupName string, name string) (pipeline.Request, error) {
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/listKeys", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// ListKeysResponder handles the response to the ListKeys request. The method always
+// closes the http.Response Body.
+func (c client) listKeysResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &AccessKeys{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// RegenerateKey regenerate Redis cache's access keys. This operation requires write permission to the cache
+// resource.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// keyType - the Redis access key to regenerate.
+func (c client) RegenerateKey(ctx context.Context, resourceGroupName string, name string, keyType KeyType) (*AccessKeys, error) {
+	if err := validation.Validate([]validation.Validation{
+		{TargetValue: string(keyType),
+			Constraints: []validation.Constraint{{Target: "keyType", Name: validation.Empty, Rule: true, Chain: nil}}}}); err != nil {
+		return nil, validation.NewError("redis.Client", "RegenerateKey", "%s", err.Error())
+	}
+	req, err := c.regenerateKeyPreparer(ctx, resourceGroupName, name, RegenerateKeyParameters{KeyType: keyType})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.regenerateKeyResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*AccessKeys), err
+}
+
+// RegenerateKeyPreparer prepares the RegenerateKey request.
+func (c client) regenerateKeyPreparer(ctx context.Context, resourceGroupName string, name string, parameters RegenerateKeyParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}/regenerateKey", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPost, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// RegenerateKeyResponder handles the response to the RegenerateKey request. The method always
+// closes the http.Response Body.
+func (c client) regenerateKeyResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &AccessKeys{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
+
+// Update update an existing Redis cache.
+// Parameters:
+// resourceGroupName - the name of the resource group.
+// name - the name of the Redis cache.
+// parameters - parameters supplied to the Update Redis operation.
+func (c client) Update(ctx context.Context, resourceGroupName string, name string, parameters UpdateParameters) (*ResourceType, error) {
+	req, err := c.updatePreparer(ctx, resourceGroupName, name, parameters)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.p.Do(ctx, runtime.NewResponderPolicyFactory(c.updateResponder), req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*ResourceType), err
+}
+
+// UpdatePreparer prepares the Update request.
+func (c client) updatePreparer(ctx context.Context, resourceGroupName string, name string, parameters UpdateParameters) (pipeline.Request, error) {
+	b, err := runtime.ToJSON(parameters)
+	if err != nil {
+		return pipeline.Request{}, pipeline.NewError(err, "failed to marshal 'parameters'")
+	}
+	u := *c.u
+	u.Path = runtime.ReplacePathParams("/subscriptions/{subscriptionId}/resourceGroups/{resourceGroupName}/providers/Microsoft.Cache/Redis/{name}", map[string]string{
+		"name":              name,
+		"resourceGroupName": resourceGroupName,
+		"subscriptionId":    c.s,
+	})
+	req, err := pipeline.NewRequest(http.MethodPatch, u, b)
+	if err != nil {
+		return req, pipeline.NewError(err, "failed to create request")
+	}
+	params := req.URL.Query()
+	params.Set("api-version", "2018-03-01")
+	req.URL.RawQuery = params.Encode()
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", UserAgent())
+	return req, nil
+}
+
+// UpdateResponder handles the response to the Update request. The method always
+// closes the http.Response Body.
+func (c client) updateResponder(resp pipeline.Response) (pipeline.Response, error) {
+	err := runtime.ValidateResponse(resp, http.StatusOK)
+	if resp == nil {
+		return nil, err
+	}
+	result := &ResourceType{rawResponse: resp.Response()}
+	if err != nil {
+		return result, err
+	}
+	return result, runtime.FromJSON(resp, result)
+}
@@ -0,0 +1,102 @@
+package redis
+
+// Copyright (c) Microsoft and contributors.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Azure/azure-pipeline-go/pipeline"
+	"github.com/jhendrixMSFT/azure-sdk-proto-go/internal/runtime"
+)
+
+// newTestClient returns a client whose requests are served by h.
+func newTestClient(t *testing.T, h http.Handler) Client {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClientWithURI(*u, "sub", pipeline.NewPipeline([]pipeline.Factory{pipeline.MethodFactoryMarker()}, pipeline.Options{}))
+}
+
+func TestCheckNameAvailability(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		status    int
+		body      string
+		available bool
+		reason    NameUnavailableReason
+	}{
+		{name: "empty body", status: http.StatusOK, available: true},
+		{name: "invalid", status: http.StatusOK, body: `{"nameAvailable":false,"reason":"Invalid","message":"bad name"}`, reason: Invalid},
+		{name: "taken", status: http.StatusConflict, body: `{"error":{"code":"NameNotAvailable","message":"name taken"}}`, reason: AlreadyExists},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/subscriptions/sub/providers/Microsoft.Cache/CheckNameAvailability" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			name, typ := "cache", "Microsoft.Cache/redis"
+			resp, err := c.Redis().CheckNameAvailability(context.Background(), CheckNameAvailabilityParameters{Name: &name, Type: &typ})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.NameAvailable == nil || *resp.NameAvailable != tc.available {
+				t.Fatalf("unexpected NameAvailable %v", resp.NameAvailable)
+			}
+			if resp.Reason != tc.reason {
+				t.Fatalf("expected reason %q, got %q", tc.reason, resp.Reason)
+			}
+			if !tc.available && resp.Message == nil {
+				t.Fatal("expected a message")
+			}
+		})
+	}
+}
+
+func TestCheckNameAvailabilityConflict(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		body string
+	}{
+		{name: "other code", body: `{"error":{"code":"ResourceGroupBeingDeleted","message":"conflict"}}`},
+		{name: "no code", body: `{}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, tc.body)
+			}))
+			name, typ := "cache", "Microsoft.Cache/redis"
+			_, err := c.Redis().CheckNameAvailability(context.Background(), CheckNameAvailabilityParameters{Name: &name, Type: &typ})
+			re, ok := err.(runtime.ResponseError)
+			if !ok {
+				t.Fatalf("expected a ResponseError, got %v", err)
+			}
+			if re.Response().StatusCode != http.StatusConflict {
+				t.Fatalf("unexpected status %d", re.Response().StatusCode)
+			}
+		})
+	}
+}